@@ -0,0 +1,116 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// buildAPI returns the API of package pkg formatted in HTML by r, as a single
+// file.
+func buildAPI(pkg *packages.Package, r *renderer) ([]File, error) {
+	src, err := apiSource(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The path does not name a real file (files starting with '_' are ignored
+	// by the go command), so type information, coverage and blame annotations
+	// are not used.
+	path := filepath.Join(pkg.Dir, "_api.go")
+	file := File{
+		ID:    htmlID(pkg.ImportPath + "/package"),
+		Name:  "package " + pkg.Name,
+		Path:  path,
+		Code:  r.render(path, src, nil),
+		input: src,
+	}
+
+	return []File{file}, nil
+}
+
+// apiSource returns the Go source code describing the API of package pkg: the
+// package documentation and the exported declarations, with their
+// documentation, in the order they appear in the source files.  Function
+// bodies, unexported struct fields and unexported interface methods are
+// removed.
+//
+// Only the .go files matching the build constraints are used.
+func apiSource(pkg *packages.Package) ([]byte, error) {
+	var doc *ast.CommentGroup
+	var decls []ast.Decl
+
+	fset := token.NewFileSet()
+	paths := append(append([]string(nil), pkg.GoFiles...), pkg.CgoFiles...)
+	sort.Strings(paths)
+	for _, path := range paths {
+		// Ignore syntax errors, using the partial AST.
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if f == nil {
+			return nil, fmt.Errorf("parse file: %v", err)
+		}
+		if doc == nil && f.Doc != nil {
+			doc = f.Doc
+		}
+		ast.FileExports(f)
+		for _, decl := range f.Decls {
+			if exportDecl(decl) {
+				decls = append(decls, decl)
+			}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if doc != nil {
+		for _, c := range doc.List {
+			fmt.Fprintln(buf, c.Text)
+		}
+	}
+	fmt.Fprintf(buf, "package %s\n", pkg.Name)
+
+	// Use the same configuration as gofmt.
+	conf := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	for _, decl := range decls {
+		buf.WriteString("\n")
+		if err := conf.Fprint(buf, fset, decl); err != nil {
+			return nil, fmt.Errorf("print declaration: %v", err)
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// exportDecl trims the declaration in place, removing the function body.  It
+// returns false if the declaration is not part of the API.  The declaration
+// must be already filtered by ast.FileExports, that removes the unexported
+// names, struct fields and interface methods.
+func exportDecl(decl ast.Decl) bool {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			// Methods of unexported types are not part of the API.
+			if !ast.IsExported(recvName(decl.Recv.List[0].Type)) {
+				return false
+			}
+		}
+		decl.Body = nil
+
+		return true
+	case *ast.GenDecl:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// TestAPISource tests that apiSource removes the unexported declarations,
+// struct fields and interface methods, and the function bodies.
+func TestAPISource(t *testing.T) {
+	const src = `// Package p is a test package.
+package p
+
+import "io"
+
+// T is exported.
+type T struct {
+	Exported   int
+	unexported int
+	A, b       string
+	io.Reader
+}
+
+type t struct {
+	X int
+}
+
+// I is exported.
+type I interface {
+	Exported()
+	unexported()
+}
+
+// M is exported.
+func (T) M() {
+	println()
+}
+
+func (t) M() {}
+
+func F() {
+	println()
+}
+
+func f() {}
+
+const C = 1
+
+const c = 2
+`
+	const want = `// Package p is a test package.
+package p
+
+// T is exported.
+type T struct {
+	Exported int
+
+	A string
+	io.Reader
+	// contains filtered or unexported fields
+}
+
+// I is exported.
+type I interface {
+	Exported()
+	// contains filtered or unexported methods
+}
+
+// M is exported.
+func (T) M()
+
+func F()
+
+const C = 1
+`
+
+	dir, err := ioutil.TempDir("", "goprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "p.go")
+	if err := ioutil.WriteFile(path, []byte(src), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := &packages.Package{Dir: dir, ImportPath: "p", Name: "p", GoFiles: []string{path}}
+	got, err := apiSource(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(got)) != strings.TrimSpace(want) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
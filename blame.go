@@ -0,0 +1,165 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Width of the blame annotation fields.
+const (
+	hashWidth   = 7
+	authorWidth = 12
+	dateWidth   = 10
+	blameWidth  = hashWidth + 1 + authorWidth + 1 + dateWidth
+)
+
+// blameLine describes the commit that last changed a source line.
+type blameLine struct {
+	Hash   string // short commit hash
+	Author string
+	Date   string
+	Group  int // index of the run of lines changed by the same commit
+}
+
+// blame returns the commit that last changed each line of the source file
+// named by path, using git blame.
+func blame(path string) ([]blameLine, error) {
+	out, err := git(filepath.Dir(path), "blame", "--porcelain", "--", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBlame(out)
+}
+
+// parseBlame parses the output of git blame --porcelain.
+func parseBlame(out []byte) ([]blameLine, error) {
+	type commit struct {
+		author string
+		time   int64
+		zone   *time.Location
+	}
+
+	var lines []blameLine
+	commits := make(map[string]*commit)
+	var cur *commit
+	var hash string
+	s := bufio.NewScanner(bytes.NewReader(out))
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The content of the line, ending the entry.
+			if cur == nil {
+				return nil, fmt.Errorf("git blame: invalid output")
+			}
+			bl := blameLine{
+				Hash:   hash[:hashWidth],
+				Author: cur.author,
+				Date:   time.Unix(cur.time, 0).In(cur.zone).Format("2006-01-02"),
+			}
+			if n := len(lines); n > 0 {
+				bl.Group = lines[n-1].Group
+				if lines[n-1].Hash != bl.Hash {
+					bl.Group++
+				}
+			}
+			lines = append(lines, bl)
+		case cur != nil && strings.HasPrefix(line, "author "):
+			cur.author = line[len("author "):]
+		case cur != nil && strings.HasPrefix(line, "author-time "):
+			t, err := strconv.ParseInt(line[len("author-time "):], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("git blame: invalid author time: %v", err)
+			}
+			cur.time = t
+		case cur != nil && strings.HasPrefix(line, "author-tz "):
+			zone, err := parseZone(line[len("author-tz "):])
+			if err != nil {
+				return nil, fmt.Errorf("git blame: invalid author zone: %v", err)
+			}
+			cur.zone = zone
+		default:
+			// A header line, with the commit hash and the line numbers,
+			// or an ignored key.
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !isHash(fields[0]) {
+				if cur == nil {
+					return nil, fmt.Errorf("git blame: invalid output")
+				}
+
+				continue
+			}
+			hash = fields[0]
+			cur = commits[hash]
+			if cur == nil {
+				cur = &commit{zone: time.UTC}
+				commits[hash] = cur
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("git blame: %v", err)
+	}
+
+	return lines, nil
+}
+
+// isHash reports whether s is a full SHA-1 or SHA-256 commit hash.
+func isHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseZone parses a time zone offset with the form +hhmm or -hhmm.
+func parseZone(s string) (*time.Location, error) {
+	if len(s) != 5 || (s[0] != '+' && s[0] != '-') {
+		return nil, fmt.Errorf("%q", s)
+	}
+	hh, err1 := strconv.Atoi(s[1:3])
+	mm, err2 := strconv.Atoi(s[3:5])
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("%q", s)
+	}
+	offset := hh*3600 + mm*60
+	if s[0] == '-' {
+		offset = -offset
+	}
+
+	return time.FixedZone(s, offset), nil
+}
+
+// String returns the blame annotation, with a fixed width of blameWidth
+// characters.
+func (b blameLine) String() string {
+	return fmt.Sprintf("%s %s %s", b.Hash, pad(b.Author, authorWidth), b.Date)
+}
+
+// pad returns s truncated or padded with spaces to width characters.
+func pad(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width-1]) + "…"
+	}
+
+	return s + strings.Repeat(" ", width-n)
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// blameOutput is the output of git blame --porcelain for a file with three
+// lines, changed by two commits.  The second commit is in a time zone where
+// the date is different from UTC.
+var blameOutput = `1111111111111111111111111111111111111111 1 1 2
+author Alice Example
+author-mail <alice@example.com>
+author-time 1700000000
+author-tz +0100
+committer Alice Example
+committer-mail <alice@example.com>
+committer-time 1700000000
+committer-tz +0100
+summary Initial commit
+boundary
+filename a.go
+	package a
+1111111111111111111111111111111111111111 2 2
+	
+2222222222222222222222222222222222222222 3 3 1
+author Bob Example
+author-mail <bob@example.com>
+author-time 1700103600
+author-tz -0500
+committer Bob Example
+committer-mail <bob@example.com>
+committer-time 1700103600
+committer-tz -0500
+summary Add a variable
+previous 1111111111111111111111111111111111111111 a.go
+filename a.go
+	var x = 1
+`
+
+// TestParseBlame tests that parseBlame returns the commit that last changed
+// each line, with the date in the author time zone.
+func TestParseBlame(t *testing.T) {
+	sha256 := strings.Repeat("3", 64)
+	var tests = []struct {
+		out   string
+		lines []blameLine
+	}{
+		{blameOutput, []blameLine{
+			{"1111111", "Alice Example", "2023-11-14", 0},
+			{"1111111", "Alice Example", "2023-11-14", 0},
+			{"2222222", "Bob Example", "2023-11-15", 1},
+		}},
+		{sha256 + " 1 1 1\nauthor Alice Example\nauthor-time 1700000000\n" +
+			"author-tz +0930\nsummary Initial commit\nfilename a.go\n\tpackage a\n",
+			[]blameLine{
+				{"3333333", "Alice Example", "2023-11-15", 0},
+			},
+		},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		lines, err := parseBlame([]byte(test.out))
+		if err != nil {
+			t.Fatalf("expected err == nil, got %q", err)
+		}
+		if !reflect.DeepEqual(lines, test.lines) {
+			t.Errorf("got %v, want %v", lines, test.lines)
+		}
+	}
+}
+
+// TestParseBlameInvalid tests that parseBlame reports an error for invalid
+// output.
+func TestParseBlameInvalid(t *testing.T) {
+	var tests = []string{
+		"\tpackage a\n",
+		"1111111 1 1 1\n\tpackage a\n",
+		strings.Repeat("1", 40) + " 1 1 1\nauthor-time x\n",
+		strings.Repeat("1", 40) + " 1 1 1\nauthor-tz 0100\n",
+	}
+
+	for _, test := range tests {
+		if _, err := parseBlame([]byte(test)); err == nil {
+			t.Errorf("%q: expected err != nil", test)
+		}
+	}
+}
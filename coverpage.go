@@ -0,0 +1,166 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// Cover represents the cover page of a document.
+type Cover struct {
+	Title     string
+	Author    string
+	Module    *packages.Module // nil if not available
+	Date      string           // date of the module version
+	Revision  string           // VCS revision, if available
+	Dirty     bool             // the working tree has uncommitted changes
+	GoVersion string
+	Command   string // command line used to generate the document
+
+	// Summary of the document content.
+	Packages int
+	Files    int
+	Lines    int
+}
+
+// newCover returns the cover page for a document with the packages in
+// pkglist, belonging to module mod.  The title is set with -title, or it
+// defaults to the module path or to the import path of the only package.
+func newCover(mod *packages.Module, pkglist []Package) *Cover {
+	c := &Cover{
+		Title:    *title,
+		Author:   *author,
+		Module:   mod,
+		Date:     mod.Date(),
+		Command:  commandLine(os.Args),
+		Packages: len(pkglist),
+	}
+	if c.Title == "" {
+		switch {
+		case mod != nil:
+			c.Title = mod.Path
+		case len(pkglist) == 1:
+			c.Title = pkglist[0].ImportPath
+		}
+	}
+
+	for _, pkg := range pkglist {
+		for _, file := range pkg.Files {
+			c.Files++
+			c.Lines += printedLines(file)
+		}
+	}
+
+	// The metadata is optional: the module may not be in a git repository,
+	// so the errors are not reported.
+	dir := sourceDir(mod, pkglist)
+	if out, err := runGit(dir, nil, "rev-parse", "--short", "HEAD"); err == nil {
+		c.Revision = strings.TrimSpace(string(out))
+		if out, err := runGit(dir, nil, "status", "--porcelain"); err == nil {
+			c.Dirty = len(out) > 0
+		}
+	}
+	if version, err := packages.GoVersion(); err == nil {
+		c.GoVersion = version
+	} else {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	return c
+}
+
+// Summary returns a summary of the document content.
+func (c *Cover) Summary() string {
+	return fmt.Sprintf("%s, %s, %s", plural(c.Packages, "package"),
+		plural(c.Files, "file"), plural(c.Lines, "line"))
+}
+
+// Fields returns the labels and the values of the non empty fields of the
+// cover page, except the title and the author.
+func (c *Cover) Fields() [][2]string {
+	var fields [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{label, value})
+		}
+	}
+
+	add("Module", c.Module.String())
+	add("Date", c.Date)
+	revision := c.Revision
+	if revision != "" && c.Dirty {
+		revision += " (modified)"
+	}
+	add("Revision", revision)
+	add("Go version", c.GoVersion)
+	add("Command", c.Command)
+	add("Contents", c.Summary())
+
+	return fields
+}
+
+// printedLines returns the number of lines of file included in the document.
+// Lines in overlapping ranges are counted once.
+func printedLines(file File) int {
+	n := lineCount(file.input)
+	if file.ranges == nil {
+		return n
+	}
+
+	ranges := append([]lineRange(nil), file.ranges...)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].First < ranges[j].First
+	})
+	count := 0
+	end := 0 // last line counted
+	for _, lr := range ranges {
+		first, last := lr.First, lr.Last
+		if first <= end {
+			first = end + 1
+		}
+		if last > n {
+			last = n
+		}
+		if last >= first {
+			count += last - first + 1
+			end = last
+		}
+	}
+
+	return count
+}
+
+// plural returns the count n of the noun, in the singular or plural form.
+func plural(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+
+	return fmt.Sprintf("%d %s", n, noun)
+}
+
+// commandLine returns the command line args as a shell command.  Arguments
+// with special characters are quoted.
+func commandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if i == 0 {
+			arg = filepath.Base(arg)
+		}
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`*?[]{}()<>|&;#~") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// parseSelector parses a declaration selector, with the form pkg.Name or
+// pkg.Type.Method, where pkg is a package pattern or a package name.  It
+// returns the package and the symbol.
+func parseSelector(s string) (pattern, sym string, err error) {
+	// The symbol starts at the first dot in the last path element.
+	i := strings.LastIndexByte(s, '/') + 1
+	j := strings.IndexByte(s[i:], '.')
+	if j <= 0 {
+		return "", "", fmt.Errorf("invalid selector %q: missing symbol", s)
+	}
+	pattern, sym = s[:i+j], s[i+j+1:]
+	if sym == "" || strings.Count(sym, ".") > 1 {
+		return "", "", fmt.Errorf("invalid selector %q", s)
+	}
+
+	return pattern, sym, nil
+}
+
+// loadSelected loads the package named by pattern.  A pattern that is a
+// single path element is first matched with the last element of the import
+// path of the packages in the main module, so that a package can be selected
+// by name.
+func loadSelected(pattern string) (*packages.Package, error) {
+	if !strings.ContainsAny(pattern, "/.") {
+		if mod, err := packages.LoadModule(""); err == nil {
+			for _, pkg := range mod.Packages {
+				if path.Base(pkg.ImportPath) == pattern {
+					return pkg, nil
+				}
+			}
+		}
+	}
+
+	return packages.Load(pattern)
+}
+
+// findDecl returns the path of the source file of package pkg with the
+// declaration of the symbol sym, and the range of lines of the declaration,
+// including its doc comment.  sym is either a name declared in the package
+// block or a method name qualified by its receiver type name.
+//
+// If test is true, findDecl will search the package pkg _test.go files.
+func findDecl(pkg *packages.Package, test bool, sym string) (string, lineRange, error) {
+	recv, name := "", sym
+	if i := strings.IndexByte(sym, '.'); i >= 0 {
+		recv, name = sym[:i], sym[i+1:]
+	}
+
+	fset := token.NewFileSet()
+	for _, path := range srcfiles(pkg, test) {
+		// Ignore syntax errors, using the partial AST.
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if f == nil {
+			return "", lineRange{}, fmt.Errorf("parse file: %v", err)
+		}
+		if node, doc := lookupDecl(f, recv, name); node != nil {
+			start := node.Pos()
+			if doc != nil {
+				start = doc.Pos()
+			}
+			lr := lineRange{
+				First: fset.Position(start).Line,
+				Last:  fset.Position(node.End()).Line,
+			}
+
+			return path, lr, nil
+		}
+	}
+
+	return "", lineRange{}, fmt.Errorf("symbol %s not found in package %s", sym, pkg)
+}
+
+// lookupDecl returns the declaration of the function or method name in file
+// f, or of the type, constant or variable name, when recv is empty.  For a
+// declaration in a group, only the spec is returned.  It also returns the doc
+// comment of the declaration, if any.
+func lookupDecl(f *ast.File, recv, name string) (ast.Node, *ast.CommentGroup) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.Name != name {
+				continue
+			}
+			hasRecv := decl.Recv != nil && len(decl.Recv.List) > 0
+			if recv == "" && !hasRecv {
+				return decl, decl.Doc
+			}
+			if hasRecv && recvName(decl.Recv.List[0].Type) == recv {
+				return decl, decl.Doc
+			}
+		case *ast.GenDecl:
+			if recv != "" {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				var doc *ast.CommentGroup
+				found := false
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					found = spec.Name.Name == name
+					doc = spec.Doc
+				case *ast.ValueSpec:
+					for _, ident := range spec.Names {
+						found = found || ident.Name == name
+					}
+					doc = spec.Doc
+				}
+				if !found {
+					continue
+				}
+				if !decl.Lparen.IsValid() {
+					// Not grouped.
+					return decl, decl.Doc
+				}
+
+				return spec, doc
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// selection describes a declaration selected by a selector.
+type selection struct {
+	pkg  *packages.Package
+	sym  string
+	path string // path of the source file
+	lr   lineRange
+}
+
+// printDecls writes on stdout an HTML or PDF document with the declarations
+// named by the selectors, in the specified order.  Each declaration is printed
+// with its doc comment, keeping the original line numbers.
+//
+// Cross references are not available, since the declaration of an
+// identifier may not be in the document.
+//
+// If test is true, printDecls will search the packages _test.go files.
+func printDecls(selectors []string, test bool) error {
+	mod, pkglist, r, err := buildDecls(selectors, test)
+	if err != nil {
+		return err
+	}
+
+	return printExcerpts(mod, pkglist, r)
+}
+
+// buildDecls returns the declarations named by the selectors formatted in
+// HTML, grouped by package, with the module of the first package and the
+// renderer used.  The arguments are the same as for printDecls.
+func buildDecls(selectors []string, test bool) (*packages.Module, []Package, *renderer, error) {
+	// Find the declarations.
+	var (
+		sels     []selection
+		pkgs     []*packages.Package
+		patterns []string
+	)
+	loaded := make(map[string]bool)
+	for _, s := range selectors {
+		pattern, sym, err := parseSelector(s)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pkg, err := loadSelected(pattern)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		path, lr, err := findDecl(pkg, test, sym)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		sels = append(sels, selection{pkg, sym, path, lr})
+		if !loaded[pkg.ImportPath] {
+			loaded[pkg.ImportPath] = true
+			pkgs = append(pkgs, pkg)
+			patterns = append(patterns, pkg.ImportPath)
+		}
+	}
+
+	// Format the declarations.
+	r, err := newRenderer(pkgs, "", patterns, test)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r.files = nil // disable cross references
+
+	var pkglist []Package
+	for _, sel := range sels {
+		input, err := ioutil.ReadFile(sel.path)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("read file %s: %v", sel.path, err)
+		}
+		ranges := []lineRange{sel.lr}
+		file := File{
+			ID:     htmlID(sel.pkg.ImportPath + "." + sel.sym),
+			Name:   filepath.Base(sel.path),
+			Path:   sel.path,
+			Code:   r.render(sel.path, input, ranges),
+			input:  input,
+			ranges: ranges,
+		}
+
+		// Group consecutive declarations of the same package.
+		if n := len(pkglist); n == 0 || pkglist[n-1].ImportPath != sel.pkg.ImportPath {
+			pkglist = append(pkglist, Package{
+				ID:         htmlID(sel.pkg.ImportPath),
+				ImportPath: sel.pkg.ImportPath,
+				Name:       sel.pkg.Name,
+				Module:     sel.pkg.Module,
+			})
+		}
+		p := &pkglist[len(pkglist)-1]
+		p.Files = append(p.Files, file)
+	}
+
+	return pkgs[0].Module, pkglist, r, nil
+}
+
+// printExcerpts writes on stdout an HTML or PDF document with the excerpts of
+// the source files in pkglist, belonging to module mod, formatted by r.
+func printExcerpts(mod *packages.Module, pkglist []Package, r *renderer) error {
+	if *format != "html" {
+		return writeFormat(os.Stdout, mod, pkglist, r)
+	}
+
+	return writeHTML(os.Stdout, indexmod, stylemod, newDocument(mod, pkglist))
+}
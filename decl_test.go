@@ -0,0 +1,64 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+)
+
+// TestBuildDecls tests that the declarations are grouped by package, with the
+// package metadata, including the module.
+func TestBuildDecls(t *testing.T) {
+	const modpath = "github.com/perillo/goprint"
+
+	selectors := []string{
+		"./internal/diff.Lines",
+		"./internal/diff.Op.String",
+		"./internal/cover.Profile",
+	}
+	mod, pkglist, _, err := buildDecls(selectors, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod == nil || mod.Path != modpath {
+		t.Errorf("got module %v, want %s", mod, modpath)
+	}
+
+	var tests = []struct {
+		importPath string
+		name       string
+		files      []string
+	}{
+		{modpath + "/internal/diff", "diff", []string{"diff.go", "diff.go"}},
+		{modpath + "/internal/cover", "cover", []string{"cover.go"}},
+	}
+	if len(pkglist) != len(tests) {
+		t.Fatalf("got %d packages, want %d", len(pkglist), len(tests))
+	}
+	for i, test := range tests {
+		pkg := pkglist[i]
+		if pkg.ImportPath != test.importPath || pkg.Name != test.name {
+			t.Errorf("package %d: got %s (%s), want %s (%s)", i, pkg.ImportPath,
+				pkg.Name, test.importPath, test.name)
+		}
+		if pkg.ID != htmlID(test.importPath) {
+			t.Errorf("package %d: got ID %q, want %q", i, pkg.ID, htmlID(test.importPath))
+		}
+		if pkg.Module == nil || pkg.Module.Path != modpath {
+			t.Errorf("package %d: got module %v, want %s", i, pkg.Module, modpath)
+		}
+		if len(pkg.Files) != len(test.files) {
+			t.Errorf("package %d: got %d files, want %d", i, len(pkg.Files), len(test.files))
+
+			continue
+		}
+		for j, file := range pkg.Files {
+			if file.Name != test.files[j] {
+				t.Errorf("package %d, file %d: got name %q, want %q", i, j,
+					file.Name, test.files[j])
+			}
+		}
+	}
+}
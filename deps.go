@@ -0,0 +1,74 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// Scopes of the dependencies to print.
+const (
+	depsModule = "module" // packages in the main module
+	depsNoStd  = "nostd"  // packages not in the standard library
+	depsAll    = "all"    // all packages
+)
+
+// printDeps writes on stdout an HTML or PDF document with all the .go source
+// files of the packages named by patterns and of all their transitive
+// dependencies in scope.  The packages are grouped by module: the main module
+// first, then the other modules sorted by path and the standard library last.
+//
+// It test is true, printDeps will use the packages _test.go files.
+func printDeps(patterns []string, scope string, test bool) error {
+	// Get packages info.
+	pkgs, err := packages.LoadDeps(patterns...)
+	if err != nil {
+		return err
+	}
+	deps := make([]*packages.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		switch {
+		case scope == depsModule && (pkg.Module == nil || !pkg.Module.Main):
+			continue
+		case scope == depsNoStd && pkg.Standard:
+			continue
+		}
+		deps = append(deps, pkg)
+	}
+	if len(deps) == 0 {
+		return fmt.Errorf("no packages in scope %s", scope)
+	}
+
+	// Group the packages by module.
+	sort.Slice(deps, func(i, j int) bool {
+		ri, rj := moduleRank(deps[i]), moduleRank(deps[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if mi, mj := deps[i].Module.String(), deps[j].Module.String(); mi != mj {
+			return mi < mj
+		}
+
+		return deps[i].ImportPath < deps[j].ImportPath
+	})
+
+	return printPackageList(commonModule(deps), deps, "", patterns, test)
+}
+
+// moduleRank returns the rank of the module containing the package pkg, in
+// the order used by printDeps.
+func moduleRank(pkg *packages.Package) int {
+	switch {
+	case pkg.Module != nil && pkg.Module.Main:
+		return 0
+	case pkg.Module != nil:
+		return 1
+	}
+
+	return 2
+}
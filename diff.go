@@ -0,0 +1,360 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/perillo/goprint/internal/diff"
+	"github.com/perillo/goprint/internal/goefmt"
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// Number of context lines in a diff hunk.
+const diffContext = 3
+
+// snapshot represents a version of a set of source files, indexed by their
+// slash separated path relative to a root directory.
+type snapshot map[string][]byte
+
+// parseRevs parses the -diff flag value, with the form old..new or old.
+func parseRevs(s string) (old, new string) {
+	if i := strings.Index(s, ".."); i >= 0 {
+		return s[:i], s[i+2:]
+	}
+
+	return s, ""
+}
+
+// printDiff writes on stdout an HTML document with the differences between
+// two versions of the .go source files of the package or module named by
+// path.  revs is the value of the -diff flag.
+//
+// In package mode, the versions are git revisions, with the working tree used
+// when the new revision is empty.  In module mode, the versions are module
+// versions, with the main module used when the new version is empty.
+//
+// If test is true, printDiff will use the _test.go files.
+func printDiff(path, revs string, test bool) error {
+	oldrev, newrev := parseRevs(revs)
+	if oldrev == "" {
+		return fmt.Errorf("invalid diff revisions %q", revs)
+	}
+
+	var (
+		heading          string // default document title
+		mod              *packages.Module
+		base             string // import path of the root directory
+		dir              string // directory of the new version
+		oldsnap, newsnap snapshot
+		err              error
+	)
+	if *module {
+		mod, err = loadVersion(path, newrev)
+		if err != nil {
+			return err
+		}
+		oldmod, err := loadVersion(mod.Path, oldrev)
+		if err != nil {
+			return err
+		}
+		oldsnap = moduleSnapshot(oldmod, test)
+		newsnap = moduleSnapshot(mod, test)
+		heading = fmt.Sprintf("%s %s..%s", mod.Path, oldmod.Version, mod.Version)
+		base = mod.Path
+		dir = mod.Dir
+	} else {
+		pkg, err := packages.Load(path)
+		if err != nil {
+			return err
+		}
+		oldsnap, err = gitSnapshot(pkg.Dir, oldrev, test)
+		if err != nil {
+			return err
+		}
+		if newrev == "" {
+			newsnap, err = dirSnapshot(pkg.Dir, srcfiles(pkg, test))
+		} else {
+			newsnap, err = gitSnapshot(pkg.Dir, newrev, test)
+		}
+		if err != nil {
+			return err
+		}
+		heading = fmt.Sprintf("%s %s..%s", pkg.ImportPath, oldrev, newrev)
+		mod = pkg.Module
+		base = pkg.ImportPath
+		dir = pkg.Dir
+	}
+
+	// Format the differences.
+	r := &renderer{
+		wrap: wrapWidth(0.6*font.Size.Points()) - 8, // two line numbers
+	}
+	pkglist := buildDiff(base, dir, oldsnap, newsnap, r)
+
+	doc := newDocument(mod, pkglist)
+	if *title == "" {
+		doc.Title = heading
+	}
+
+	return writeHTML(os.Stdout, indexdiff, stylemod, doc)
+}
+
+// loadVersion loads the specified version of the module named by path.  When
+// version is empty, the module is loaded from its directory; otherwise it is
+// downloaded to the module cache, if necessary.
+func loadVersion(path, version string) (*packages.Module, error) {
+	pattern := path
+	if version != "" {
+		if path == "" {
+			// Use the main module path.
+			main, err := packages.LoadModule("")
+			if err != nil {
+				return nil, err
+			}
+			pattern = main.Path
+		}
+		pattern += "@" + version
+	}
+	mod, err := packages.LoadModule(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if mod.Dir == "" {
+		return nil, fmt.Errorf("module %s not in the module cache", pattern)
+	}
+
+	return mod, nil
+}
+
+// moduleSnapshot returns a snapshot of the source files of all the packages
+// in module mod.  Files that can not be read are reported as a warning.
+//
+// If test is true, moduleSnapshot will use each package _test.go files.
+func moduleSnapshot(mod *packages.Module, test bool) snapshot {
+	var paths []string
+	for _, pkg := range mod.Packages {
+		paths = append(paths, srcfiles(pkg, test)...)
+	}
+	snap, err := dirSnapshot(mod.Dir, paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	return snap
+}
+
+// dirSnapshot returns a snapshot of the files in paths, relative to dir.
+func dirSnapshot(dir string, paths []string) (snapshot, error) {
+	snap := make(snapshot, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return snap, err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return snap, fmt.Errorf("read file %s: %v", path, err)
+		}
+		snap[filepath.ToSlash(rel)] = data
+	}
+
+	return snap, nil
+}
+
+// gitSnapshot returns a snapshot of the .go files in the directory dir at the
+// git revision rev.  Subdirectories are ignored.
+//
+// If test is true, gitSnapshot will use the _test.go files.
+func gitSnapshot(dir, rev string, test bool) (snapshot, error) {
+	out, err := git(dir, "ls-tree", "--name-only", rev, "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	snap := make(snapshot)
+	for _, name := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") != test {
+			continue
+		}
+		data, err := git(dir, "show", rev+":./"+name)
+		if err != nil {
+			return nil, err
+		}
+		snap[name] = data
+	}
+
+	return snap, nil
+}
+
+// git invokes the git command in the directory dir, returning its output.
+func git(dir string, args ...string) ([]byte, error) {
+	return runGit(dir, os.Stderr, args...)
+}
+
+// runGit is like git, but the git error output is written on stderr.  When
+// stderr is nil, the error output is discarded.
+func runGit(dir string, stderr io.Writer, args ...string) ([]byte, error) {
+	stdout := new(bytes.Buffer)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %v", strings.Join(args, " "), err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// buildDiff returns the differences between the oldsnap and newsnap snapshots
+// formatted in HTML by r, grouped by package.  base is the import path and dir
+// the directory of the root of the new snapshot.  Files that are equal are not
+// included.
+func buildDiff(base, dir string, oldsnap, newsnap snapshot, r *renderer) []Package {
+	var names []string
+	for name := range oldsnap {
+		names = append(names, name)
+	}
+	for name := range newsnap {
+		if _, ok := oldsnap[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// Keep the files in the same directory together.
+		a, b := names[i], names[j]
+		if x, y := path.Dir(a), path.Dir(b); x != y {
+			return x < y
+		}
+
+		return a < b
+	})
+
+	var pkglist []Package
+	for _, name := range names {
+		a, b := oldsnap[name], newsnap[name]
+		if bytes.Equal(a, b) {
+			continue
+		}
+
+		importPath := path.Join(base, path.Dir(name))
+		if n := len(pkglist); n == 0 || pkglist[n-1].ImportPath != importPath {
+			pkglist = append(pkglist, Package{
+				ID:         htmlID(importPath),
+				ImportPath: importPath,
+			})
+		}
+		code, ranges := r.renderDiff(name, a, b)
+		file := File{
+			ID:     htmlID(importPath + "/" + path.Base(name)),
+			Name:   path.Base(name),
+			Path:   filepath.Join(dir, filepath.FromSlash(name)),
+			Code:   code,
+			input:  b,
+			ranges: ranges,
+		}
+		p := &pkglist[len(pkglist)-1]
+		p.Files = append(p.Files, file)
+	}
+
+	return pkglist
+}
+
+// formatLines returns the formatted lines of the Go source code in input.
+func formatLines(name string, input []byte) []goefmt.Line {
+	var lines []goefmt.Line
+	if len(input) == 0 {
+		return lines
+	}
+	for line := range goefmt.Format(goefmt.Scan(name, input)) {
+		lines = append(lines, line)
+	}
+	// Remove the empty line following the last eol.
+	if n := len(lines); n > 0 && len(lines[n-1]) == 0 && input[len(input)-1] == '\n' {
+		lines = lines[:n-1]
+	}
+
+	return lines
+}
+
+// renderDiff returns an HTML fragment containing the unified diff between the
+// oldsrc and newsrc versions of the Go source file named by path, and the
+// ranges of the lines of the new version included in the diff.  Each line is
+// highlighted using the goefmt lexer on its side, and is preceded by the line
+// numbers in the old and new versions.
+//
+// Each line of the diff is an HTML block, so no eol is added.
+func (r *renderer) renderDiff(path string, oldsrc, newsrc []byte) (template.HTML, []lineRange) {
+	ranges := []lineRange{}
+	buf := new(bytes.Buffer)
+
+	name := filepath.Base(path)
+	a, b := formatLines(name, oldsrc), formatLines(name, newsrc)
+	text := func(lines []goefmt.Line) []string {
+		s := make([]string, len(lines))
+		for i, l := range lines {
+			s[i] = l.String()
+		}
+
+		return s
+	}
+
+	for _, h := range diff.Unified(text(a), text(b), diffContext) {
+		fmt.Fprintf(buf, "<span class=\"hunk\">@@ -%d,%d +%d,%d @@</span>",
+			h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		if h.NewLines > 0 {
+			ranges = append(ranges, lineRange{h.NewStart, h.NewStart + h.NewLines - 1})
+		}
+		for _, e := range h.Edits {
+			var (
+				line       goefmt.Line
+				class      = "ctx"
+				mark       = " "
+				oldn, newn string
+			)
+			switch e.Op {
+			case diff.Equal:
+				line = b[e.New]
+				oldn, newn = fmt.Sprint(e.Old+1), fmt.Sprint(e.New+1)
+			case diff.Delete:
+				line = a[e.Old]
+				class, mark = "del", "-"
+				oldn = fmt.Sprint(e.Old + 1)
+			case diff.Insert:
+				line = b[e.New]
+				class, mark = "add", "+"
+				newn = fmt.Sprint(e.New + 1)
+			}
+			rows := goefmt.Wrap(line, r.wrap, tabsize)
+			for i, l := range rows {
+				rest := false
+				if i > 0 {
+					oldn, newn = "", continuation
+					rest = split(rows[i-1], l)
+				}
+				fmt.Fprintf(buf, "<span class=\"%s\"><span class=\"line\">%4s %4s</span> "+
+					"<span class=\"mark\">%s</span> %s</span>", class, oldn, newn, mark,
+					r.lineToHTML(path, l, rest))
+			}
+		}
+	}
+
+	return template.HTML(buf.String()), ranges
+}
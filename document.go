@@ -0,0 +1,155 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/perillo/goprint/internal/css"
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// document is the context of the HTML templates.  The field names are a
+// stable interface for the templates specified with the -template and -css
+// flags.
+type document struct {
+	Title string // document title
+
+	// Package is the printed package in package mode, and nil otherwise.
+	Package *packages.Package
+
+	// Module is the module all the printed packages belong to, or nil.
+	Module *packages.Module
+
+	// Packages are the printed packages; in package mode it contains only
+	// Package.
+	Packages []Package
+
+	// Files and Coverage are the files and the coverage of Package, in
+	// package mode.
+	Files    []File
+	Coverage *Coverage
+
+	Index []Symbol // symbol index, if available
+	TOC   bool     // print a table of contents, in package mode
+	Cover *Cover   // cover page, if requested
+
+	// Page layout.
+	Columns    int
+	NUp        int
+	PageSize   css.PageSize
+	PageMargin css.PageMargin
+	Font       css.Font
+	Theme      template.CSS
+	Margins    template.CSS // page margin boxes
+	FontFaces  template.CSS // embedded fonts
+}
+
+// newDocument returns a new document with the packages in pkglist, belonging
+// to module mod, and the page layout specified on the command line.
+func newDocument(mod *packages.Module, pkglist []Package) *document {
+	return &document{
+		Title:      docTitle(mod),
+		Module:     mod,
+		Packages:   pkglist,
+		Columns:    *columns,
+		NUp:        *nup,
+		PageSize:   sheetSize(),
+		PageMargin: pageMargin,
+		Font:       font,
+		Theme:      template.CSS(codeTheme.CSS()),
+	}
+}
+
+// docTitle returns the title of a document with packages belonging to module
+// mod: the title set with -title, the module or, when the packages belong to
+// different modules, the patterns on the command line.
+func docTitle(mod *packages.Module) string {
+	switch {
+	case *title != "":
+		return *title
+	case mod != nil:
+		return mod.String()
+	}
+
+	return strings.Join(flag.Args(), " ")
+}
+
+// funcs are the helper functions available to the templates.
+var funcs = template.FuncMap{
+	"now":      time.Now,
+	"env":      os.Getenv,
+	"base":     path.Base,
+	"join":     strings.Join,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"coverage": coverageLabel,
+}
+
+// loadTemplate returns the template set with the index.html, style.css and
+// diff.css templates.  The index and style templates are replaced by the
+// content of the files specified with the -template and -css flags, if set.
+func loadTemplate(index, style string) (*template.Template, error) {
+	tmpl := template.New("index.html").Funcs(funcs)
+	if err := parseTemplate(tmpl, index, *templateFile); err != nil {
+		return nil, err
+	}
+	if err := parseTemplate(tmpl.New("style.css"), style, *cssFile); err != nil {
+		return nil, err
+	}
+	template.Must(tmpl.New("diff.css").Parse(stylediff))
+
+	return tmpl, nil
+}
+
+// parseTemplate parses the template text into tmpl, or the content of the file
+// named by path if not empty.
+func parseTemplate(tmpl *template.Template, text, path string) error {
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("load template: %v", err)
+		}
+		text = string(data)
+	}
+	if _, err := tmpl.Parse(text); err != nil {
+		return fmt.Errorf("parse template: %v", err)
+	}
+
+	return nil
+}
+
+// writeHTML writes on w the HTML document for doc, using the index and style
+// templates.
+func writeHTML(w io.Writer, index, style string, doc *document) error {
+	tmpl, err := loadTemplate(index, style)
+	if err != nil {
+		return err
+	}
+	doc.Margins, err = boxCSS(doc.Package, doc.Module, sourceDir(doc.Module, doc.Packages))
+	if err != nil {
+		return err
+	}
+	doc.FontFaces, err = fontFaces(font.Family, fontFiles)
+	if err != nil {
+		return err
+	}
+	if *coverPage {
+		doc.Cover = newCover(doc.Module, doc.Packages)
+	}
+	if err := tmpl.Execute(w, doc); err != nil {
+		return fmt.Errorf("execute: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/perillo/goprint/internal/goefmt"
+	"github.com/perillo/goprint/internal/packages"
+	"github.com/perillo/goprint/internal/textfmt"
+)
+
+// Kinds of the non-Go files that can be included.
+const (
+	kindAsm    = "asm"    // Go assembly source files
+	kindC      = "c"      // cgo C source and header files
+	kindEmbed  = "embed"  // files embedded with //go:embed
+	kindGoMod  = "gomod"  // go.mod file of the module
+	kindReadme = "readme" // README files of the module
+	kindAll    = "all"
+)
+
+// extraKinds is the set of the kinds of non-Go files to include, set with
+// the -include flag.
+var extraKinds map[string]bool
+
+// parseKinds parses a comma separated list of kinds of non-Go files.
+func parseKinds(s string) (map[string]bool, error) {
+	kinds := make(map[string]bool)
+	if s == "" {
+		return kinds, nil
+	}
+	for _, kind := range strings.Split(s, ",") {
+		switch kind {
+		case kindAsm, kindC, kindEmbed, kindGoMod, kindReadme:
+			kinds[kind] = true
+		case kindAll:
+			for _, kind := range []string{kindAsm, kindC, kindEmbed, kindGoMod, kindReadme} {
+				kinds[kind] = true
+			}
+		default:
+			return nil, fmt.Errorf("invalid kind of files: %q", kind)
+		}
+	}
+
+	return kinds, nil
+}
+
+// extraFiles returns the non-Go files of package pkg to include.  The go.mod
+// and README files of the module are included in the package in the module
+// root directory.
+func extraFiles(pkg *packages.Package) []string {
+	var paths []string
+	if extraKinds[kindGoMod] || extraKinds[kindReadme] {
+		if mod := pkg.Module; mod != nil && mod.Dir != "" && mod.Dir == pkg.Dir {
+			if extraKinds[kindGoMod] {
+				path := filepath.Join(mod.Dir, "go.mod")
+				if _, err := os.Stat(path); err == nil {
+					paths = append(paths, path)
+				}
+			}
+			if extraKinds[kindReadme] {
+				readme, _ := filepath.Glob(filepath.Join(mod.Dir, "README*"))
+				paths = append(paths, readme...)
+			}
+		}
+	}
+	if extraKinds[kindAsm] {
+		paths = append(paths, pkg.SFiles...)
+	}
+	if extraKinds[kindC] {
+		paths = append(paths, pkg.CFiles...)
+		paths = append(paths, pkg.HFiles...)
+	}
+	if extraKinds[kindEmbed] {
+		paths = append(paths, pkg.EmbedFiles...)
+	}
+
+	return paths
+}
+
+// buildExtra returns the non-Go files of package pkg formatted in HTML by r.
+// Binary files are skipped, with a warning.
+func buildExtra(pkg *packages.Package, r *renderer) ([]File, error) {
+	var files []File
+	for _, path := range extraFiles(pkg) {
+		input, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read file %s: %v", path, err)
+		}
+		if !utf8.Valid(input) || bytes.IndexByte(input, 0) >= 0 {
+			fmt.Fprintf(os.Stderr, "warning: skipping binary file %s\n", path)
+
+			continue
+		}
+
+		// Embedded files can be in subdirectories.
+		name, err := filepath.Rel(pkg.Dir, path)
+		if err != nil {
+			name = filepath.Base(path)
+		}
+		files = append(files, File{
+			ID:    htmlID(pkg.ImportPath + "/" + name),
+			Name:  name,
+			Path:  path,
+			Code:  r.render(path, input, nil),
+			input: input,
+		})
+	}
+
+	return files, nil
+}
+
+// formatFile returns the lines of the source file named by path, with the
+// specified content.  Go source files are formatted with goefmt, and the other
+// files with textfmt, using the language detected from the file name.
+func formatFile(path string, input []byte) chan goefmt.Line {
+	name := filepath.Base(path)
+	if strings.HasSuffix(name, ".go") {
+		return goefmt.Format(goefmt.Scan(name, input))
+	}
+
+	return textfmt.Format(textfmt.Detect(name), input)
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"strings"
+)
+
+// fontFiles are the font files to embed in the document.
+var fontFiles []string
+
+// fontVariants are the font-weight and font-style of the font files specified
+// with -font-file, in order.
+var fontVariants = [...]struct {
+	weight, style string
+}{
+	{"normal", "normal"},
+	{"bold", "normal"},
+	{"normal", "italic"},
+	{"bold", "italic"},
+}
+
+// parseFontFiles parses the comma separated list of font files, in the order
+// regular, bold, italic and bold italic.
+func parseFontFiles(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	paths := strings.Split(s, ",")
+	if len(paths) > len(fontVariants) {
+		return nil, fmt.Errorf("too many font files: %d", len(paths))
+	}
+
+	return paths, nil
+}
+
+// fontFormat returns the media type and the CSS format of the font file with
+// the specified content.
+func fontFormat(data []byte) (mediaType, format string, err error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0, 1, 0, 0}), bytes.HasPrefix(data, []byte("true")):
+		return "font/ttf", "truetype", nil
+	case bytes.HasPrefix(data, []byte("OTTO")):
+		return "font/otf", "opentype", nil
+	}
+
+	return "", "", fmt.Errorf("unsupported font format")
+}
+
+// fontFaces returns the @font-face rules embedding the font files in paths,
+// with the variants in fontVariants order, for the font family.  Missing
+// variants are synthesized by the browser.
+func fontFaces(family string, paths []string) (template.CSS, error) {
+	var buf strings.Builder
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("load font: %v", err)
+		}
+		mediaType, format, err := fontFormat(data)
+		if err != nil {
+			return "", fmt.Errorf("load font %s: %v", path, err)
+		}
+		v := fontVariants[i]
+		fmt.Fprintf(&buf, "@font-face {\n\tfont-family: %s;\n\tfont-weight: %s;\n"+
+			"\tfont-style: %s;\n\tsrc: url(data:%s;base64,%s) format(%s);\n}\n\n",
+			cssString(family), v.weight, v.style, mediaType,
+			base64.StdEncoding.EncodeToString(data), cssString(format))
+	}
+
+	return template.CSS(buf.String()), nil
+}
@@ -7,61 +7,386 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"go/token"
 	"html"
 	"html/template"
 	"strings"
 
+	"github.com/perillo/goprint/internal/cover"
 	"github.com/perillo/goprint/internal/goefmt"
+	"github.com/perillo/goprint/internal/packages"
+	"github.com/perillo/goprint/internal/semantic"
 )
 
 // File represents an HTML formatted Go source file.
 type File struct {
-	Name string
-	Code template.HTML
+	ID       string // HTML id
+	Name     string
+	Path     string
+	Code     template.HTML
+	Coverage *Coverage // nil if not available
+
+	input  []byte      // original source code
+	ranges []lineRange // lines to print, all if nil
+}
+
+// lineRange represents a range of lines in a source file.  Line numbers start
+// from 1, and Last is inclusive.
+type lineRange struct {
+	First, Last int
+}
+
+// inRanges returns true if the line number n is in one of the ranges.  All the
+// lines are in a nil ranges.
+func inRanges(ranges []lineRange, n int) bool {
+	if ranges == nil {
+		return true
+	}
+	for _, lr := range ranges {
+		if lr.First <= n && n <= lr.Last {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Package represents an HTML formatted Go package.
 type Package struct {
+	ID         string // HTML id
 	ImportPath string
 	Name       string
 	Files      []File
+	Coverage   *Coverage        // nil if not available
+	Module     *packages.Module // nil if not available
+}
+
+// Coverage represents the statement coverage of a file or package.
+type Coverage struct {
+	Covered int
+	Total   int
+}
+
+// String implements the Stringer interface.
+func (c *Coverage) String() string {
+	if c.Total == 0 {
+		return "no statements"
+	}
+
+	return fmt.Sprintf("%.1f%%", 100*float64(c.Covered)/float64(c.Total))
+}
+
+// coverageLabel returns the description of the statement coverage of a file
+// and of its package, omitting the parts that are not available.
+func coverageLabel(file, pkg *Coverage) string {
+	var parts []string
+	if file != nil {
+		parts = append(parts, "file "+file.String())
+	}
+	if pkg != nil {
+		parts = append(parts, "package "+pkg.String())
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// sumCoverage returns the total coverage of files, or nil if the coverage is
+// not available for any file.
+func sumCoverage(files []File) *Coverage {
+	var sum *Coverage
+	for _, f := range files {
+		if f.Coverage == nil {
+			continue
+		}
+		if sum == nil {
+			sum = new(Coverage)
+		}
+		sum.Covered += f.Coverage.Covered
+		sum.Total += f.Coverage.Total
+	}
+
+	return sum
+}
+
+// renderer renders Go source files in HTML.
+type renderer struct {
+	prog *semantic.Program // type information, can be nil
+
+	// files maps the path of each source file in the document to the prefix
+	// of the HTML id used for the declarations in the file.
+	files map[string]string
+
+	// cover maps the path of each source file in the document to its coverage
+	// profile, if available.
+	cover map[string]*cover.Profile
+
+	// blame maps the path of each source file in the document to the commit
+	// that last changed each line, when the -blame flag is set.
+	blame map[string][]blameLine
+
+	wrap int // maximum number of characters in a code line
 }
 
+// Wrapped lines are marked with the continuation glyph in place of the line
+// number, and skipped lines with the elision glyph.  Tabs are expanded as in
+// the CSS style.
+const (
+	continuation = "\u00bb" // right-pointing double angle quotation mark
+	elision      = "\u2026" // horizontal ellipsis
+	tabsize      = 4
+)
+
 // render returns an HTML fragment containing the formatted Go code for the
-// specified source file.  A line number is printed at the begin of each line.
-func render(name string, input []byte) template.HTML {
+// source file named by path.  A line number is printed at the begin of each
+// line.  Only the lines in ranges are included, keeping their line numbers,
+// and each run of skipped lines is replaced by an elision marker; all the
+// lines are included if ranges is nil.  Lines longer than r.wrap
+// characters are wrapped at token boundaries.
+// When the coverage profile is available, covered and uncovered lines are
+// shaded.  When blame information is available, the commit that last changed
+// each line is printed after the line number, only for the first line of each
+// run of lines changed by the same commit.
+//
+// Identifiers are classified using the type information in r.prog, if not
+// nil.  Uses of identifiers declared in a file of the document are linked to
+// their declaration.
+func (r *renderer) render(path string, input []byte, ranges []lineRange) template.HTML {
 	buf := new(bytes.Buffer)
 
 	n := 1
-	for line := range goefmt.Format(goefmt.Scan(name, input)) {
+	last := 0 // last line included
+	status := r.lineStatus(path, input)
+	for line := range formatFile(path, input) {
+		if !inRanges(ranges, n) {
+			n++
+
+			continue
+		}
+		if n > last+1 {
+			fmt.Fprintf(buf, "<span class=\"line elision\">%3s</span>\n", elision)
+		}
+		last = n
 		if line == nil {
 			// Empty line
-			fmt.Fprintf(buf, "<span class=\"line empty\">%3d</span>\n", n)
-		} else {
-			fmt.Fprintf(buf, "<span class=\"line\">%3d</span> %s\n", n,
-				lineToHTML(line))
+			fmt.Fprintf(buf, "<span class=\"line empty\">%3d</span>%s\n", n,
+				r.blameToHTML(path, n, false))
+			n++
+
+			continue
+		}
+
+		// Shade the lines according to their coverage status.
+		start, end := "", ""
+		if n <= len(status) && status[n-1] != cover.NotInstrumented {
+			start = fmt.Sprintf("<span class=\"%s\">", status[n-1])
+			end = "</span>"
+		}
+		rows := goefmt.Wrap(line, r.wrap, tabsize)
+		for i, l := range rows {
+			if i > 0 {
+				fmt.Fprintf(buf, "%s<span class=\"line cont\">%3s</span>%s %s%s\n",
+					start, continuation, r.blameToHTML(path, n, true),
+					r.lineToHTML(path, l, split(rows[i-1], l)), end)
+
+				continue
+			}
+			fmt.Fprintf(buf, "%s<span class=\"line\">%3d</span>%s %s%s\n", start, n,
+				r.blameToHTML(path, n, false), r.lineToHTML(path, l, false), end)
 		}
 		n++
 	}
+	if last < lineCount(input) {
+		fmt.Fprintf(buf, "<span class=\"line elision\">%3s</span>\n", elision)
+	}
 
 	return template.HTML(buf.String())
 }
 
-// spanToHTML returns an HTML representation for the code span.
-func spanToHTML(s *goefmt.Span) string {
+// lineCount returns the number of lines in the source file with the specified
+// content.
+func lineCount(input []byte) int {
+	n := bytes.Count(input, []byte("\n"))
+	if len(input) > 0 && input[len(input)-1] != '\n' {
+		n++
+	}
+
+	return n
+}
+
+// lineStatus returns the coverage status of each line of the source file
+// named by path, with the specified content.  It returns nil if the coverage
+// profile is not available.
+func (r *renderer) lineStatus(path string, input []byte) []cover.Status {
+	p, ok := r.cover[path]
+	if !ok {
+		return nil
+	}
+
+	return p.Lines(input)
+}
+
+// coverage returns the statement coverage of the source file named by path,
+// or nil if the coverage profile is not available.
+func (r *renderer) coverage(path string) *Coverage {
+	p, ok := r.cover[path]
+	if !ok {
+		return nil
+	}
+	covered, total := p.Statements()
+
+	return &Coverage{covered, total}
+}
+
+// blameAnnotation returns the blame annotation for the line number n of the
+// source file named by path, and the index of the run of lines it belongs to.
+// The annotation is blank for continuation lines and lines in the middle of a
+// run.  ok is false if the blame information is not available.
+func (r *renderer) blameAnnotation(path string, n int, cont bool) (s string, group int, ok bool) {
+	lines := r.blame[path]
+	if n > len(lines) {
+		return "", 0, false
+	}
+	b := lines[n-1]
+	if cont || (n > 1 && lines[n-2].Group == b.Group) {
+		return strings.Repeat(" ", blameWidth), b.Group, true
+	}
+
+	return b.String(), b.Group, true
+}
+
+// blameToHTML returns an HTML representation for the blame annotation of the
+// line number n of the source file named by path, including the leading space.
+// Runs of lines changed by the same commit are shaded alternately.
+func (r *renderer) blameToHTML(path string, n int, cont bool) string {
+	s, group, ok := r.blameAnnotation(path, n, cont)
+	if !ok {
+		return ""
+	}
+	class := "blame"
+	if group%2 == 1 {
+		class += " odd"
+	}
+
+	return fmt.Sprintf(` <span class="%s">%s</span>`, class, html.EscapeString(s))
+}
+
+// spanClass returns the HTML class for the code span in the source file named
+// by path.  When the span is an identifier and r.prog has type information for
+// it, the class returned by goefmt.TokenClass is refined with the kind of the
+// identifier, and shadowed builtins are correctly handled.
+func (r *renderer) spanClass(path string, s *goefmt.Span) []string {
+	if s.Token == token.IDENT && r.prog != nil {
+		if id, ok := r.prog.Lookup(path, s.Offset); ok {
+			return id.Class()
+		}
+	}
+
+	return goefmt.TokenClass(s)
+}
+
+// anchor returns the HTML id for the declaration at pos, if the declaration
+// is in a file of the document.
+func (r *renderer) anchor(pos token.Position) (string, bool) {
+	prefix, ok := r.files[pos.Filename]
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s-%d", prefix, pos.Offset), true
+}
+
+// xref returns the HTML id and the position of the declaration of the entity
+// denoted by the identifier in the code span of the source file named by path.
+// ok is false if the span is not an identifier or the declaration is not in a
+// file of the document.
+func (r *renderer) xref(path string, s *goefmt.Span) (id string, decl token.Position, ok bool) {
+	if s.Token != token.IDENT || r.prog == nil {
+		return "", decl, false
+	}
+	decl, ok = r.prog.Decl(path, s.Offset)
+	if !ok {
+		return "", decl, false
+	}
+	id, ok = r.anchor(decl)
+
+	return id, decl, ok
+}
+
+// htmlID returns a valid HTML id from s, replacing all the characters that are
+// not letters, digits, '.', '_' and '-' with '-'.  The returned id does not
+// need to be escaped when used in an URL fragment.
+func htmlID(s string) string {
+	valid := func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		case r == '.' || r == '_' || r == '-':
+			return r
+		}
+
+		return '-'
+	}
+
+	return strings.Map(valid, s)
+}
+
+// split reports whether the first span of the wrapped line l is the rest of a
+// token split by goefmt.Wrap at the end of the previous wrapped line prev.
+func split(prev, l goefmt.Line) bool {
+	if len(prev) == 0 || len(l) == 0 {
+		return false
+	}
+	last := prev[len(prev)-1]
+
+	return last.Offset >= 0 && last.Offset == l[0].Offset && last.Whitespace == ""
+}
+
+// isDecl reports whether the code span s in the source file named by path is
+// the declaration at decl.  When the span is the rest of an identifier split
+// by goefmt.Wrap, only the first part is the declaration, so that the HTML id
+// is unique.
+func isDecl(path string, s *goefmt.Span, decl token.Position, rest bool) bool {
+	return !rest && decl.Filename == path && decl.Offset == s.Offset
+}
+
+// spanToHTML returns an HTML representation for the code span in the source
+// file named by path.  rest is true if the span is the rest of a token split
+// by goefmt.Wrap.
+func (r *renderer) spanToHTML(path string, s *goefmt.Span, rest bool) string {
 	if s.Code == "" {
 		// Only horizontal white space.
 		return s.Whitespace
 	}
-	class := strings.Join(goefmt.TokenClass(s), " ")
+	class := strings.Join(r.spanClass(path, s), " ")
 	code := html.EscapeString(s.Code)
 
+	if id, decl, ok := r.xref(path, s); ok {
+		id = html.EscapeString(id)
+		switch {
+		case isDecl(path, s, decl, rest):
+			// The identifier is the declaration.
+			return fmt.Sprintf(`<span id="%s" class="%s">%s</span>%s`,
+				id, class, code, s.Whitespace)
+		case decl.Filename == path && decl.Offset == s.Offset:
+			// The rest of the declaration.
+			return fmt.Sprintf(`<span class="%s">%s</span>%s`, class, code, s.Whitespace)
+		case decl.Filename == path:
+			return fmt.Sprintf(`<a class="xref" href="#%s"><span class="%s">%s</span></a>%s`,
+				id, class, code, s.Whitespace)
+		default:
+			// Declaration in another file.
+			return fmt.Sprintf(`<a class="xref external" href="#%s"><span class="%s">%s</span></a>%s`,
+				id, class, code, s.Whitespace)
+		}
+	}
+
 	return fmt.Sprintf(`<span class="%s">%s</span>%s`, class, code, s.Whitespace)
 }
 
-// lineToHTML returns an HTML representation for the code line.  The eol is not
-// included.
-func lineToHTML(l goefmt.Line) string {
+// lineToHTML returns an HTML representation for the code line in the source
+// file named by path.  The eol is not included.  rest is true if the first
+// span of the line is the rest of a token split by goefmt.Wrap.
+func (r *renderer) lineToHTML(path string, l goefmt.Line, rest bool) string {
 	if l == nil {
 		// Empty line.
 		return ""
@@ -69,7 +394,7 @@ func lineToHTML(l goefmt.Line) string {
 
 	spans := make([]string, len(l))
 	for i, span := range l {
-		spans[i] = spanToHTML(span)
+		spans[i] = r.spanToHTML(path, span, rest && i == 0)
 	}
 
 	return strings.Join(spans, "")
@@ -0,0 +1,79 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/perillo/goprint/internal/goefmt"
+	"github.com/perillo/goprint/internal/semantic"
+)
+
+// TestLineToHTMLWrapped tests that when goefmt.Wrap splits a declaration
+// identifier, only the first part has the HTML id.
+func TestLineToHTMLWrapped(t *testing.T) {
+	const src = "package p\n\nfunc ThisIsAVeryLongFunctionName() {}\n"
+
+	dir, err := ioutil.TempDir("", "goprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.go")
+	if err := ioutil.WriteFile(path, []byte(src), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &renderer{
+		prog: semantic.Load([]*semantic.Source{{
+			ImportPath: "p",
+			Name:       "p",
+			Files:      []string{path},
+		}}, nil),
+		files: map[string]string{path: "p-a.go"},
+		wrap:  10,
+	}
+	var lines []string
+	for line := range formatFile(path, []byte(src)) {
+		rows := goefmt.Wrap(line, r.wrap, tabsize)
+		for i, l := range rows {
+			lines = append(lines, r.lineToHTML(path, l, i > 0 && split(rows[i-1], l)))
+		}
+	}
+	html := strings.Join(lines, "\n")
+
+	const id = `id="p-a.go-16"`
+	if n := strings.Count(html, id); n != 1 {
+		t.Errorf("got %d HTML ids %s, want 1, in:\n%s", n, id, html)
+	}
+}
+
+// TestCoverageLabel tests that coverageLabel includes only the available
+// parts of the coverage.
+func TestCoverageLabel(t *testing.T) {
+	file := &Coverage{Covered: 1, Total: 4}
+	pkg := &Coverage{Covered: 3, Total: 4}
+
+	var tests = []struct {
+		file, pkg *Coverage
+		label     string
+	}{
+		{nil, nil, ""},
+		{file, nil, "file 25.0%"},
+		{nil, pkg, "package 75.0%"},
+		{file, pkg, "file 25.0%, package 75.0%"},
+	}
+
+	for _, test := range tests {
+		if got := coverageLabel(test.file, test.pkg); got != test.label {
+			t.Errorf("coverageLabel(%v, %v): got %q, want %q", test.file,
+				test.pkg, got, test.label)
+		}
+	}
+}
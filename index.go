@@ -0,0 +1,123 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// Symbol represents a declaration in the symbol index.
+type Symbol struct {
+	Name       string // for methods, it is qualified with the receiver type
+	Kind       string // type, func, method, const or var
+	ImportPath string
+	File       string
+	ID         string // HTML id of the declaration, or empty if not printed
+}
+
+// buildIndex returns the symbol index for the source files of all the
+// packages in pkglist, sorted alphabetically.  The HTML id of each declaration
+// is the one generated by r.
+//
+// If test is true, buildIndex will use each package _test.go files.
+func buildIndex(pkglist []*packages.Package, test bool, r *renderer) ([]Symbol, error) {
+	var index []Symbol
+
+	fset := token.NewFileSet()
+	for _, pkg := range pkglist {
+		for _, path := range srcfiles(pkg, test) {
+			// Ignore syntax errors, using the partial AST.
+			f, err := parser.ParseFile(fset, path, nil, 0)
+			if f == nil {
+				return nil, fmt.Errorf("parse file: %v", err)
+			}
+
+			add := func(ident *ast.Ident, kind string, name string) {
+				if ident.Name == "_" {
+					return
+				}
+				pos := fset.Position(ident.Pos())
+				id, _ := r.anchor(pos)
+				sym := Symbol{
+					Name:       name,
+					Kind:       kind,
+					ImportPath: pkg.ImportPath,
+					File:       filepath.Base(path),
+					ID:         id,
+				}
+				index = append(index, sym)
+			}
+			for _, decl := range f.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if decl.Recv == nil || len(decl.Recv.List) == 0 {
+						add(decl.Name, "func", decl.Name.Name)
+
+						continue
+					}
+					recv := recvName(decl.Recv.List[0].Type)
+					add(decl.Name, "method", recv+"."+decl.Name.Name)
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						switch spec := spec.(type) {
+						case *ast.TypeSpec:
+							add(spec.Name, "type", spec.Name.Name)
+						case *ast.ValueSpec:
+							kind := "var"
+							if decl.Tok == token.CONST {
+								kind = "const"
+							}
+							for _, name := range spec.Names {
+								add(name, kind, name.Name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(index, func(i, j int) bool {
+		a, b := index[i], index[j]
+		if x, y := strings.ToLower(a.Name), strings.ToLower(b.Name); x != y {
+			return x < y
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+
+		return a.ImportPath < b.ImportPath
+	})
+
+	return index, nil
+}
+
+// recvName returns the name of the receiver base type expr.
+func recvName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return recvName(expr.X)
+	case *ast.ParenExpr:
+		return recvName(expr.X)
+	case *ast.IndexExpr:
+		// Generic type with one type parameter.
+		return recvName(expr.X)
+	case *ast.IndexListExpr:
+		// Generic type with more type parameters.
+		return recvName(expr.X)
+	}
+
+	return "?"
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// TestRecvName tests that recvName returns the name of the receiver base type
+// of a method declaration.
+func TestRecvName(t *testing.T) {
+	var tests = []struct {
+		decl string
+		name string
+	}{
+		{"func (T) M()", "T"},
+		{"func (t *T) M()", "T"},
+		{"func (t (*T)) M()", "T"},
+		{"func (T[K]) M()", "T"},
+		{"func (t *T[K]) M()", "T"},
+		{"func (T[K, V]) M()", "T"},
+		{"func (t *T[K, V, _]) M()", "T"},
+	}
+
+	for _, test := range tests {
+		src := "package p\n\n" + test.decl + " {}\n"
+		f, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", test.decl, err)
+		}
+		decl := f.Decls[0].(*ast.FuncDecl)
+		if got := recvName(decl.Recv.List[0].Type); got != test.name {
+			t.Errorf("%s: got %q, want %q", test.decl, got, test.name)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cover implements parsing of the coverage profiles written by
+// go test -coverprofile.
+package cover
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Block represents a block of statements in a coverage profile.  Columns are
+// byte offsets, starting from 1; the end column is exclusive.
+type Block struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	NumStmt             int
+	Count               int
+}
+
+// Profile represents the coverage profile of a source file.
+type Profile struct {
+	FileName string // import path of the package, followed by the file name
+	Mode     string // set, count or atomic
+	Blocks   []Block
+}
+
+// Status represents the coverage status of a source line.
+type Status int
+
+// Supported statuses.
+const (
+	NotInstrumented Status = iota
+	Covered
+	Uncovered
+)
+
+var statuses = [...]string{
+	NotInstrumented: "",
+	Covered:         "covered",
+	Uncovered:       "uncovered",
+}
+
+// String implements the Stringer interface.  The returned string is suitable
+// to be used as an HTML class.
+func (s Status) String() string {
+	return statuses[s]
+}
+
+// Parse parses the coverage profile read from r, returning the profile of each
+// source file indexed by file name.  Blocks reported more than once, as when
+// profiles of several packages are merged, are combined.
+func Parse(r io.Reader) (map[string]*Profile, error) {
+	profiles := make(map[string]*Profile)
+
+	mode := ""
+	n := 0
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		n++
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "mode: ") {
+			mode = line[len("mode: "):]
+
+			continue
+		}
+		if mode == "" {
+			return nil, fmt.Errorf("line %d: missing mode line", n)
+		}
+
+		name, b, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		p, ok := profiles[name]
+		if !ok {
+			p = &Profile{
+				FileName: name,
+				Mode:     mode,
+			}
+			profiles[name] = p
+		}
+		p.Blocks = append(p.Blocks, b)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range profiles {
+		p.merge()
+	}
+
+	return profiles, nil
+}
+
+// parseLine parses a profile line, with the form
+// name:startLine.startCol,endLine.endCol numStmt count.
+func parseLine(line string) (string, Block, error) {
+	var b Block
+
+	i := strings.LastIndexByte(line, ':')
+	if i < 0 {
+		return "", b, fmt.Errorf("invalid line %q", line)
+	}
+	name := line[:i]
+	fields := strings.FieldsFunc(line[i+1:], func(r rune) bool {
+		return r == '.' || r == ',' || r == ' '
+	})
+	if len(fields) != 6 {
+		return "", b, fmt.Errorf("invalid line %q", line)
+	}
+	values := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return "", b, fmt.Errorf("invalid line %q", line)
+		}
+		values[i] = v
+	}
+	b = Block{
+		StartLine: values[0],
+		StartCol:  values[1],
+		EndLine:   values[2],
+		EndCol:    values[3],
+		NumStmt:   values[4],
+		Count:     values[5],
+	}
+
+	return name, b, nil
+}
+
+// merge sorts the blocks and combines duplicate blocks.
+func (p *Profile) merge() {
+	sort.SliceStable(p.Blocks, func(i, j int) bool {
+		a, b := p.Blocks[i], p.Blocks[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+
+		return a.StartCol < b.StartCol
+	})
+
+	blocks := p.Blocks[:0]
+	for _, b := range p.Blocks {
+		n := len(blocks)
+		if n > 0 && blocks[n-1].StartLine == b.StartLine &&
+			blocks[n-1].StartCol == b.StartCol &&
+			blocks[n-1].EndLine == b.EndLine && blocks[n-1].EndCol == b.EndCol {
+			if p.Mode == "set" {
+				if b.Count > 0 {
+					blocks[n-1].Count = 1
+				}
+			} else {
+				blocks[n-1].Count += b.Count
+			}
+
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	p.Blocks = blocks
+}
+
+// Statements returns the number of covered statements and the total number of
+// statements.
+func (p *Profile) Statements() (covered, total int) {
+	for _, b := range p.Blocks {
+		total += b.NumStmt
+		if b.Count > 0 {
+			covered += b.NumStmt
+		}
+	}
+
+	return covered, total
+}
+
+// Lines returns the coverage status of each line in src, the content of the
+// profiled source file.
+//
+// A line is uncovered when some code in the line belongs to a block that was
+// not executed, and it is covered when all the code in the line belongs to
+// blocks that were executed.  Leading and trailing white space is ignored, so
+// that a block starting at the end of a line does not affect it.
+func (p *Profile) Lines(src []byte) []Status {
+	lines := strings.Split(string(src), "\n")
+	status := make([]Status, len(lines))
+	for _, b := range p.Blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+		for n := b.StartLine; n <= b.EndLine && n <= len(lines); n++ {
+			line := lines[n-1]
+			first := len(line) - len(strings.TrimLeft(line, " \t")) + 1
+			last := len(strings.TrimRight(line, " \t\r"))
+			if first > last {
+				continue // empty line
+			}
+
+			start, end := 1, len(line)+1
+			if n == b.StartLine {
+				start = b.StartCol
+			}
+			if n == b.EndLine {
+				end = b.EndCol
+			}
+			if start > last || end <= first {
+				continue
+			}
+			switch {
+			case b.Count == 0:
+				status[n-1] = Uncovered
+			case status[n-1] == NotInstrumented:
+				status[n-1] = Covered
+			}
+		}
+	}
+
+	return status
+}
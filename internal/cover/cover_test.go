@@ -0,0 +1,73 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+const src = `package p
+
+func f(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+`
+
+const profile = `mode: set
+example.com/p/p.go:3.19,4.11 1 1
+example.com/p/p.go:5.3,6.1 1 0
+example.com/p/p.go:8.2,8.10 1 1
+example.com/p/p.go:3.19,4.11 1 0
+example.com/p/p.go:5.3,6.1 1 0
+example.com/p/p.go:8.2,8.10 1 1
+`
+
+func TestParse(t *testing.T) {
+	profiles, err := Parse(strings.NewReader(profile))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p, ok := profiles["example.com/p/p.go"]
+	if !ok {
+		t.Fatalf("missing profile for p.go")
+	}
+	if len(p.Blocks) != 3 {
+		t.Errorf("got %d blocks, want 3", len(p.Blocks))
+	}
+	if covered, total := p.Statements(); covered != 2 || total != 3 {
+		t.Errorf("got %d/%d covered statements, want 2/3", covered, total)
+	}
+
+	want := []Status{
+		NotInstrumented, NotInstrumented, Covered, Covered, Uncovered,
+		NotInstrumented, NotInstrumented, Covered, NotInstrumented,
+	}
+	got := p.Lines([]byte(src))
+	for i, s := range want {
+		if got[i] != s {
+			t.Errorf("line %d: got status %q, want %q", i+1, got[i], s)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	var tests = []string{
+		"example.com/p/p.go:3.19,4.11 1 1\n",
+		"mode: set\nexample.com/p/p.go 1 1\n",
+		"mode: set\nexample.com/p/p.go:3.19,4.11 1\n",
+		"mode: set\nexample.com/p/p.go:3.x,4.11 1 1\n",
+	}
+
+	for _, test := range tests {
+		if _, err := Parse(strings.NewReader(test)); err == nil {
+			t.Errorf("Parse(%q): expected error", test)
+		}
+	}
+}
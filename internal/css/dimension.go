@@ -106,6 +106,21 @@ func (d Dimension) String() string {
 	return fmt.Sprintf("%v%s", d.Value, d.Unit)
 }
 
+// points is the number of points in each supported unit.
+var points = map[Unit]float64{
+	NoUnit:     0, // only for zero lengths
+	Point:      1,
+	Pica:       12,
+	Inch:       72,
+	Millimeter: 72 / 25.4,
+	Centimeter: 72 / 2.54,
+}
+
+// Points returns the length of the dimension in points.
+func (d Dimension) Points() float64 {
+	return float64(d.Value) * points[d.Unit]
+}
+
 func numberToken(ch rune) bool {
 	// No scientific notation.
 	return strings.ContainsRune("-0123456789.", ch)
@@ -6,6 +6,7 @@ package css
 
 import (
 	"fmt"
+	"math"
 	"testing"
 )
 
@@ -104,3 +105,27 @@ func TestInvalidDimension(t *testing.T) {
 		})
 	}
 }
+
+// TestDimensionPoints tests the conversion of a Dimension to points.
+func TestDimensionPoints(t *testing.T) {
+	var tests = []struct {
+		value  Dimension
+		points float64
+	}{
+		{Dimension{0, NoUnit}, 0},
+		{Dimension{10, Point}, 10},
+		{Dimension{1, Pica}, 12},
+		{Dimension{1, Inch}, 72},
+		{Dimension{25.4, Millimeter}, 72},
+		{Dimension{2.54, Centimeter}, 72},
+	}
+
+	for _, test := range tests {
+		t.Run(mkname(test.value.String()), func(t *testing.T) {
+			points := test.value.Points()
+			if math.Abs(points-test.points) > 1e-9 {
+				t.Errorf("got %v, want %v", points, test.points)
+			}
+		})
+	}
+}
@@ -15,30 +15,214 @@ import (
 	"strings"
 )
 
-// PageSize represents a CSS page size.  Only A4 and letter page sizes are
-// supported.  The page orientation is portrait and can not be changed.
-type PageSize string
+// Orientation represents the orientation of a page.
+type Orientation string
 
-// Supported page sizes.
+// Supported orientations.  When the orientation is not specified, named page
+// sizes use the portrait orientation and explicit page sizes are used as is.
 const (
-	A4     PageSize = "A4"
-	Letter PageSize = "letter"
+	NoOrientation Orientation = ""
+	Portrait      Orientation = "portrait"
+	Landscape     Orientation = "landscape"
 )
 
+// PageSize represents a CSS page size.  It is either a named page size or an
+// explicit size, with an optional orientation.
+type PageSize struct {
+	Name        string    // page size name, or empty for explicit sizes
+	Width       Dimension // width as specified, without orientation
+	Height      Dimension // height as specified, without orientation
+	Orientation Orientation
+}
+
+// pageSizes contains the supported named page sizes, in portrait orientation.
+var pageSizes = makePageSizes()
+
+// Page size names defined by the CSS specification.  The other named page
+// sizes are represented using explicit sizes.
+var cssPageSizes = map[string]bool{
+	"A5": true, "A4": true, "A3": true, "B5": true, "B4": true,
+	"JIS-B5": true, "JIS-B4": true, "letter": true, "legal": true,
+	"ledger": true,
+}
+
+func makePageSizes() map[string][2]Dimension {
+	pageSizes := make(map[string][2]Dimension)
+	mm := func(w, h Number) [2]Dimension {
+		return [2]Dimension{{w, Millimeter}, {h, Millimeter}}
+	}
+	in := func(w, h Number) [2]Dimension {
+		return [2]Dimension{{w, Inch}, {h, Inch}}
+	}
+
+	// ISO 216 A, B and C series, from 0 to 10.
+	iso := [][3][2]Number{
+		{{841, 1189}, {1000, 1414}, {917, 1297}},
+		{{594, 841}, {707, 1000}, {648, 917}},
+		{{420, 594}, {500, 707}, {458, 648}},
+		{{297, 420}, {353, 500}, {324, 458}},
+		{{210, 297}, {250, 353}, {229, 324}},
+		{{148, 210}, {176, 250}, {162, 229}},
+		{{105, 148}, {125, 176}, {114, 162}},
+		{{74, 105}, {88, 125}, {81, 114}},
+		{{52, 74}, {62, 88}, {57, 81}},
+		{{37, 52}, {44, 62}, {40, 57}},
+		{{26, 37}, {31, 44}, {28, 40}},
+	}
+	for n, sizes := range iso {
+		for i, series := range []string{"A", "B", "C"} {
+			name := fmt.Sprintf("%s%d", series, n)
+			pageSizes[name] = mm(sizes[i][0], sizes[i][1])
+		}
+	}
+
+	// JIS B series, only the sizes defined by CSS.
+	pageSizes["JIS-B5"] = mm(182, 257)
+	pageSizes["JIS-B4"] = mm(257, 364)
+
+	// US sizes.
+	pageSizes["letter"] = in(8.5, 11)
+	pageSizes["legal"] = in(8.5, 14)
+	pageSizes["ledger"] = in(11, 17)
+
+	return pageSizes
+}
+
+// Common page sizes.
+var (
+	A4     = named("A4")
+	Letter = named("letter")
+)
+
+// named returns the named page size with the specified name.
+func named(name string) PageSize {
+	size := pageSizes[name]
+
+	return PageSize{Name: name, Width: size[0], Height: size[1]}
+}
+
+// lookup returns the canonical name of the named page size, ignoring case as
+// required by CSS.
+func lookup(name string) (string, bool) {
+	for ent := range pageSizes {
+		if strings.EqualFold(ent, name) {
+			return ent, true
+		}
+	}
+
+	return "", false
+}
+
+// Size returns the width and height of the page, according to the
+// orientation.
+func (p PageSize) Size() (width, height Dimension) {
+	w, h := p.Width, p.Height
+	switch p.Orientation {
+	case Portrait:
+		if w.Points() > h.Points() {
+			w, h = h, w
+		}
+	case Landscape:
+		if w.Points() < h.Points() {
+			w, h = h, w
+		}
+	}
+
+	return w, h
+}
+
 // String implements the Stringer interface.
 func (p PageSize) String() string {
-	return fmt.Sprintf("%s portrait", string(p))
+	if cssPageSizes[p.Name] {
+		orientation := p.Orientation
+		if orientation == NoOrientation {
+			orientation = Portrait
+		}
+
+		return fmt.Sprintf("%s %s", p.Name, orientation)
+	}
+
+	// The orientation can not be used with explicit sizes.
+	w, h := p.Size()
+	if w == h {
+		return w.String()
+	}
+
+	return fmt.Sprintf("%v %v", w, h)
 }
 
 // Set implements the Value interface.
+//
+// The syntax is the same as the CSS size property, with the exception that
+// the orientation can also be used with explicit sizes and that auto is not
+// supported.
 func (p *PageSize) Set(s string) error {
-	if strings.TrimSpace(s) == "" {
-		return fmt.Errorf("invalid page size: %q", s)
+	var v PageSize
+	var dims []Dimension
+	mkerr := func(s string, err error) error {
+		return fmt.Errorf("invalid page size: %q: %v", s, err)
 	}
-	v := PageSize(s)
-	if v != A4 && v != Letter {
+
+	l := strings.Fields(s)
+	if len(l) == 0 || len(l) > 3 {
 		return fmt.Errorf("invalid page size: %q", s)
 	}
+	for _, field := range l {
+		switch o := Orientation(strings.ToLower(field)); o {
+		case Portrait, Landscape:
+			if v.Orientation != NoOrientation {
+				return mkerr(s, fmt.Errorf("duplicate orientation"))
+			}
+			v.Orientation = o
+
+			continue
+		}
+		if name, ok := lookup(field); ok {
+			if v.Name != "" || len(dims) > 0 {
+				return fmt.Errorf("invalid page size: %q", s)
+			}
+			v = PageSize{
+				Name:        name,
+				Width:       pageSizes[name][0],
+				Height:      pageSizes[name][1],
+				Orientation: v.Orientation,
+			}
+
+			continue
+		}
+
+		var d Dimension
+		if err := d.Set(field); err != nil {
+			return mkerr(s, err)
+		}
+		if d.Value == 0 {
+			return mkerr(s, fmt.Errorf("zero length"))
+		}
+		if v.Name != "" || len(dims) == 2 {
+			return fmt.Errorf("invalid page size: %q", s)
+		}
+		dims = append(dims, d)
+	}
+
+	switch len(dims) {
+	case 0:
+		if v.Name == "" {
+			// Only the orientation was specified.
+			v = PageSize{
+				Name:        A4.Name,
+				Width:       A4.Width,
+				Height:      A4.Height,
+				Orientation: v.Orientation,
+			}
+		}
+	case 1:
+		// A square page.
+		v.Width = dims[0]
+		v.Height = dims[0]
+	case 2:
+		v.Width = dims[0]
+		v.Height = dims[1]
+	}
 
 	*p = v
 
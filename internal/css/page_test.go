@@ -15,6 +15,23 @@ func TestPageSize(t *testing.T) {
 	}{
 		{"A4", A4},
 		{"letter", Letter},
+		{"a4", A4},
+		{"A4 portrait", PageSize{"A4", Dimension{210, Millimeter},
+			Dimension{297, Millimeter}, Portrait}},
+		{"landscape A3", PageSize{"A3", Dimension{297, Millimeter},
+			Dimension{420, Millimeter}, Landscape}},
+		{"landscape", PageSize{"A4", Dimension{210, Millimeter},
+			Dimension{297, Millimeter}, Landscape}},
+		{"C5", PageSize{"C5", Dimension{162, Millimeter},
+			Dimension{229, Millimeter}, NoOrientation}},
+		{"ledger", PageSize{"ledger", Dimension{11, Inch},
+			Dimension{17, Inch}, NoOrientation}},
+		{"210mm 297mm", PageSize{"", Dimension{210, Millimeter},
+			Dimension{297, Millimeter}, NoOrientation}},
+		{"10cm", PageSize{"", Dimension{10, Centimeter},
+			Dimension{10, Centimeter}, NoOrientation}},
+		{"210mm 297mm landscape", PageSize{"", Dimension{210, Millimeter},
+			Dimension{297, Millimeter}, Landscape}},
 	}
 
 	for _, test := range tests {
@@ -35,7 +52,9 @@ func TestPageSize(t *testing.T) {
 // when a invalid input is provided.
 func TestInvalidPageSize(t *testing.T) {
 	var tests = []string{
-		"", " ", "A2",
+		"", " ", "A11", "A4 letter", "A4 10cm", "10cm A4",
+		"portrait landscape", "10cm 10cm 10cm", "0 10cm", "10px",
+		"A4 portrait portrait",
 	}
 
 	for _, test := range tests {
@@ -49,6 +68,38 @@ func TestInvalidPageSize(t *testing.T) {
 	}
 }
 
+// TestPageSizeFull tests the Value interface implementation for the PageSize
+// type, using both the Set and String methods.
+func TestPageSizeFull(t *testing.T) {
+	var tests = []struct {
+		input  string
+		output string
+	}{
+		{"A4", "A4 portrait"},
+		{"a4 landscape", "A4 landscape"},
+		{"JIS-B5", "JIS-B5 portrait"},
+		{"C5", "162mm 229mm"},
+		{"C5 landscape", "229mm 162mm"},
+		{"297mm 210mm", "297mm 210mm"},
+		{"297mm 210mm portrait", "210mm 297mm"},
+		{"10cm 10cm", "10cm"},
+	}
+
+	for _, test := range tests {
+		t.Run(mkname(test.input), func(t *testing.T) {
+			var p PageSize
+			err := p.Set(test.input)
+			if err != nil {
+				t.Fatalf("expected err == nil, got %q", err)
+			}
+			output := p.String()
+			if output != test.output {
+				t.Errorf("got %q, want %q", output, test.output)
+			}
+		})
+	}
+}
+
 // TestPageMargin tests the Value implementation for the PageMargin type, when
 // a valid input is provided.
 func TestPageMargin(t *testing.T) {
@@ -0,0 +1,241 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package diff implements the computation of line based differences, using
+// the Myers' algorithm.
+package diff
+
+// Op represents the operation of an edit.
+type Op int
+
+// Supported operations.
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+var ops = [...]string{
+	Equal:  "equal",
+	Delete: "delete",
+	Insert: "insert",
+}
+
+// String implements the Stringer interface.
+func (op Op) String() string {
+	return ops[op]
+}
+
+// Edit represents a line in the edit script transforming a sequence of lines
+// into another.
+type Edit struct {
+	Op  Op
+	Old int // index of the line in the old sequence, -1 for Insert
+	New int // index of the line in the new sequence, -1 for Delete
+}
+
+// Hunk represents a group of changes, with the surrounding context, as in a
+// unified diff.
+type Hunk struct {
+	OldStart, OldLines int // OldStart is 1-based
+	NewStart, NewLines int // NewStart is 1-based
+	Edits              []Edit
+}
+
+// Lines returns the shortest edit script transforming the lines in a into the
+// lines in b.
+//
+// It uses the linear space refinement of the Myers' algorithm, finding the
+// middle snake of the shortest path and recursively solving the two halves.
+func Lines(a, b []string) []Edit {
+	d := &differ{a: a, b: b}
+	max := (len(a)+len(b)+1)/2 + 1
+	d.vf = make([]int, 2*max+1)
+	d.vb = make([]int, 2*max+1)
+	d.compare(0, len(a), 0, len(b))
+
+	return d.edits
+}
+
+// differ holds the state of the computation of an edit script.
+type differ struct {
+	a, b  []string
+	vf    []int // furthest reaching x of the forward paths, by diagonal
+	vb    []int // furthest reaching x of the reverse paths, by diagonal
+	edits []Edit
+}
+
+// compare appends to d.edits the edit script transforming a[a0:a1] into
+// b[b0:b1].
+func (d *differ) compare(a0, a1, b0, b1 int) {
+	// Skip the common prefix and suffix.
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.edits = append(d.edits, Edit{Equal, a0, b0})
+		a0++
+		b0++
+	}
+	n := 0
+	for a0 < a1-n && b0 < b1-n && d.a[a1-n-1] == d.b[b1-n-1] {
+		n++
+	}
+	a1, b1 = a1-n, b1-n
+
+	switch {
+	case a0 == a1:
+		for y := b0; y < b1; y++ {
+			d.edits = append(d.edits, Edit{Insert, -1, y})
+		}
+	case b0 == b1:
+		for x := a0; x < a1; x++ {
+			d.edits = append(d.edits, Edit{Delete, x, -1})
+		}
+	default:
+		x, y, u, v := d.middleSnake(a0, a1, b0, b1)
+		d.compare(a0, x, b0, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.edits = append(d.edits, Edit{Equal, x, y})
+		}
+		d.compare(u, a1, v, b1)
+	}
+
+	for i := 0; i < n; i++ {
+		d.edits = append(d.edits, Edit{Equal, a1 + i, b1 + i})
+	}
+}
+
+// middleSnake returns the start (x, y) and the end (u, v) of the middle snake
+// of the shortest path transforming a[a0:a1] into b[b0:b1], searching from
+// both ends at the same time.  Both the sequences must not be empty, and
+// their first and last elements must differ.
+func (d *differ) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	off := len(d.vf) / 2 // offset of the diagonal 0
+	d.vf[off+1] = 0
+	d.vb[off+1] = 0
+
+	for step := 0; step <= (n+m+1)/2; step++ {
+		// Forward paths, with x and y relative to (a0, b0).
+		for k := -step; k <= step; k += 2 {
+			if k == -step || (k != step && d.vf[off+k-1] < d.vf[off+k+1]) {
+				x = d.vf[off+k+1] // down: insert
+			} else {
+				x = d.vf[off+k-1] + 1 // right: delete
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && d.a[a0+u] == d.b[b0+v] {
+				u++
+				v++
+			}
+			d.vf[off+k] = u
+			if kr := delta - k; odd && -step < kr && kr < step && u+d.vb[off+kr] >= n {
+				return a0 + x, b0 + y, a0 + u, b0 + v
+			}
+		}
+
+		// Reverse paths, with x and y relative to (a1, b1).
+		for k := -step; k <= step; k += 2 {
+			if k == -step || (k != step && d.vb[off+k-1] < d.vb[off+k+1]) {
+				x = d.vb[off+k+1]
+			} else {
+				x = d.vb[off+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && d.a[a1-u-1] == d.b[b1-v-1] {
+				u++
+				v++
+			}
+			d.vb[off+k] = u
+			if kf := delta - k; !odd && -step <= kf && kf <= step && u+d.vf[off+kf] >= n {
+				return a1 - u, b1 - v, a1 - x, b1 - y
+			}
+		}
+	}
+
+	panic("diff: middle snake not found")
+}
+
+// Unified returns the hunks of the unified diff between the lines in a and the
+// lines in b, with the specified number of context lines.  It returns nil if a
+// and b are equal.
+func Unified(a, b []string, context int) []Hunk {
+	edits := Lines(a, b)
+
+	var hunks []Hunk
+	for i := 0; i < len(edits); {
+		// Find the next change.
+		for i < len(edits) && edits[i].Op == Equal {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk while the changes are separated by at most
+		// 2*context equal lines.
+		end := i
+		for end < len(edits) {
+			for end < len(edits) && edits[end].Op != Equal {
+				end++
+			}
+			j := end
+			for j < len(edits) && edits[j].Op == Equal {
+				j++
+			}
+			if j == len(edits) || j-end > 2*context {
+				break
+			}
+			end = j
+		}
+		stop := end + context
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+
+		hunks = append(hunks, newHunk(edits[start:stop], edits[:start]))
+		i = stop
+	}
+
+	return hunks
+}
+
+// newHunk returns the hunk with the specified edits, where before are all the
+// edits preceding the hunk.
+func newHunk(edits, before []Edit) Hunk {
+	var h Hunk
+
+	h.Edits = edits
+	for _, e := range before {
+		if e.Op != Insert {
+			h.OldStart++
+		}
+		if e.Op != Delete {
+			h.NewStart++
+		}
+	}
+	for _, e := range edits {
+		if e.Op != Insert {
+			h.OldLines++
+		}
+		if e.Op != Delete {
+			h.NewLines++
+		}
+	}
+	// As in GNU diff, an empty range starts at the line before.
+	if h.OldLines > 0 {
+		h.OldStart++
+	}
+	if h.NewLines > 0 {
+		h.NewStart++
+	}
+
+	return h
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// apply applies the edit script to a, checking that it is consistent.
+func apply(a, b []string, edits []Edit) ([]string, error) {
+	var out []string
+
+	i, j := 0, 0
+	for _, e := range edits {
+		switch e.Op {
+		case Equal:
+			if e.Old != i || e.New != j || a[i] != b[j] {
+				return nil, fmt.Errorf("invalid edit %v at %d, %d", e, i, j)
+			}
+			out = append(out, a[i])
+			i++
+			j++
+		case Delete:
+			if e.Old != i {
+				return nil, fmt.Errorf("invalid edit %v at %d, %d", e, i, j)
+			}
+			i++
+		case Insert:
+			if e.New != j {
+				return nil, fmt.Errorf("invalid edit %v at %d, %d", e, i, j)
+			}
+			out = append(out, b[j])
+			j++
+		}
+	}
+	if i != len(a) || j != len(b) {
+		return nil, fmt.Errorf("incomplete edit script")
+	}
+
+	return out, nil
+}
+
+func TestLines(t *testing.T) {
+	var tests = []struct {
+		a, b    string
+		changes int
+	}{
+		{"", "", 0},
+		{"a", "", 1},
+		{"", "a", 1},
+		{"abc", "abc", 0},
+		{"abcabba", "cbabac", 5},
+		{"abc", "xyz", 6},
+		{"abcdef", "abxdef", 2},
+		{"abcdef", "acdf", 2},
+	}
+
+	for _, test := range tests {
+		a := strings.Split(test.a, "")
+		b := strings.Split(test.b, "")
+		edits := Lines(a, b)
+		got, err := apply(a, b, edits)
+		if err != nil {
+			t.Errorf("Lines(%q, %q): %v", test.a, test.b, err)
+
+			continue
+		}
+		if strings.Join(got, "") != test.b {
+			t.Errorf("Lines(%q, %q): got %q", test.a, test.b, strings.Join(got, ""))
+		}
+		if n := changes(edits); n != test.changes {
+			t.Errorf("Lines(%q, %q): got %d changes, want %d", test.a, test.b,
+				n, test.changes)
+		}
+	}
+}
+
+// changes returns the number of the edits that are not Equal.
+func changes(edits []Edit) int {
+	n := 0
+	for _, e := range edits {
+		if e.Op != Equal {
+			n++
+		}
+	}
+
+	return n
+}
+
+// TestLinesLarge tests the edit script of large and mostly rewritten inputs,
+// where the shortest path has a number of changes close to the total number
+// of lines.
+func TestLinesLarge(t *testing.T) {
+	const n = 5000
+
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range a {
+		a[i] = fmt.Sprintf("old %d", i)
+		b[i] = fmt.Sprintf("new %d", i)
+		if i%500 == 0 {
+			a[i] = fmt.Sprintf("same %d", i)
+			b[i] = a[i]
+		}
+	}
+
+	edits := Lines(a, b)
+	if _, err := apply(a, b, edits); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := changes(edits), 2*(n-n/500); got != want {
+		t.Errorf("got %d changes, want %d", got, want)
+	}
+}
+
+// TestLinesRandom tests that the edit script of random inputs is the shortest,
+// comparing the number of changes with the one computed from the length of
+// the longest common subsequence.
+func TestLinesRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func() []string {
+		s := make([]string, rnd.Intn(30))
+		for i := range s {
+			s[i] = string(rune('a' + rnd.Intn(3)))
+		}
+
+		return s
+	}
+
+	for i := 0; i < 1000; i++ {
+		a, b := random(), random()
+		edits := Lines(a, b)
+		if _, err := apply(a, b, edits); err != nil {
+			t.Fatalf("Lines(%q, %q): %v", a, b, err)
+		}
+		if got, want := changes(edits), len(a)+len(b)-2*lcs(a, b); got != want {
+			t.Fatalf("Lines(%q, %q): got %d changes, want %d", a, b, got, want)
+		}
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	l := make([][]int, len(a)+1)
+	for i := range l {
+		l[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				l[i][j] = l[i+1][j+1] + 1
+			case l[i+1][j] > l[i][j+1]:
+				l[i][j] = l[i+1][j]
+			default:
+				l[i][j] = l[i][j+1]
+			}
+		}
+	}
+
+	return l[0][0]
+}
+
+func TestUnified(t *testing.T) {
+	a := strings.Split("abcdefghijklmnopqrstuvwxyz", "")
+	b := strings.Split("abcdefgHijklmnopqrstuvwxyZ", "")
+
+	hunks := Unified(a, b, 3)
+	want := []Hunk{
+		{OldStart: 5, OldLines: 7, NewStart: 5, NewLines: 7},
+		{OldStart: 23, OldLines: 4, NewStart: 23, NewLines: 4},
+	}
+	if len(hunks) != len(want) {
+		t.Fatalf("got %d hunks, want %d", len(hunks), len(want))
+	}
+	for i, h := range hunks {
+		h.Edits = nil
+		if h.OldStart != want[i].OldStart || h.OldLines != want[i].OldLines ||
+			h.NewStart != want[i].NewStart || h.NewLines != want[i].NewLines {
+			t.Errorf("hunk %d: got %+v, want %+v", i, h, want[i])
+		}
+	}
+
+	// Changes near each other are merged.
+	if n := len(Unified(a, b, 9)); n != 1 {
+		t.Errorf("got %d hunks, want 1", n)
+	}
+	if hunks := Unified(a, a, 3); hunks != nil {
+		t.Errorf("got %d hunks for equal lines, want 0", len(hunks))
+	}
+	if hunks := Unified(nil, []string{"a"}, 3); hunks[0].OldStart != 0 {
+		t.Errorf("got old start %d for insertion in empty file, want 0",
+			hunks[0].OldStart)
+	}
+}
@@ -17,6 +17,14 @@ type Span struct {
 	Token      token.Token
 	Code       string
 	Whitespace string
+	// Offset is the byte offset of the token in the source file.
+	// For the additional lines of a general comment or raw string literal
+	// spanning multiple lines, it is the offset of the token.
+	// For spans with only white space, it is -1.
+	Offset int
+	// Class is the HTML class of the span, when it is not derived from the
+	// token, as for source files that are not Go source files.
+	Class []string
 }
 
 // String implements the Stringer interface.
@@ -50,7 +58,11 @@ func (f *formatter) run1() {
 		if isAtEOL(tok) {
 			// The next token will be on a new line; add this token code and
 			// emit the complete line.
-			line = append(line, &Span{Token: tok.Value, Code: tok.Code})
+			line = append(line, &Span{
+				Token:  tok.Value,
+				Code:   tok.Code,
+				Offset: tok.Offset(),
+			})
 			f.lines <- line
 
 			// Avoid extra allocations.
@@ -67,12 +79,12 @@ func (f *formatter) run1() {
 			if len(ws) > 0 {
 				// Add a span with only horizontal white space to the start of
 				// the next line.
-				line = append(line, &Span{Whitespace: ws})
+				line = append(line, &Span{Whitespace: ws, Offset: -1})
 			}
 
 			continue
 		}
-		line = append(line, &Span{tok.Value, tok.Code, tok.Whitespace})
+		line = append(line, &Span{tok.Value, tok.Code, tok.Whitespace, tok.Offset(), nil})
 	}
 	f.lines <- line
 	close(f.lines)
@@ -95,21 +107,21 @@ Loop:
 			// Split current line in three parts.
 			// First emit spans on the left side, including the first line of
 			// the offending comment or string.
-			lhs := Span{Token: span.Token, Code: span.Code[:pos]}
+			lhs := Span{Token: span.Token, Code: span.Code[:pos], Offset: span.Offset}
 			f.out <- append(line[:i], &lhs)
 
 			// Then emit additional lines in the comment or string, excluding
 			// the last one.
 			extra := strings.Split(span.Code[pos+1:], "\n")
 			for _, code := range extra[:len(extra)-1] {
-				ent := Span{Token: span.Token, Code: code}
+				ent := Span{Token: span.Token, Code: code, Offset: span.Offset}
 				f.out <- Line{&ent}
 			}
 
 			// Finally emit remaining spans on the right size, including the
 			// last line of the offending comment or string, adding white
 			// space.
-			rhs := Span{span.Token, extra[len(extra)-1], span.Whitespace}
+			rhs := Span{span.Token, extra[len(extra)-1], span.Whitespace, span.Offset, nil}
 			f.out <- append(Line{&rhs}, line[i+1:]...)
 
 			continue Loop
@@ -12,6 +12,10 @@ import (
 
 // TokenClass returns the HTML class for the specified code span.
 func TokenClass(span *Span) []string {
+	if span.Class != nil {
+		return span.Class
+	}
+
 	// Avoid extra allocation.
 	class := make([]string, 0, 2)
 
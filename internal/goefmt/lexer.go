@@ -36,6 +36,11 @@ func (t *Token) String() string {
 	return t.Code + t.Whitespace
 }
 
+// Offset returns the byte offset of the token in the source file.
+func (t *Token) Offset() int {
+	return t.pos.Offset
+}
+
 type lexer struct {
 	input  string
 	file   *token.File
@@ -45,6 +50,7 @@ type lexer struct {
 }
 
 func (l *lexer) run1() {
+	end := 0 // offset of the end of the previous token
 	for {
 		p, tok, lit := l.s.Scan()
 		if tok == token.EOF {
@@ -61,7 +67,15 @@ func (l *lexer) run1() {
 			// Remove the "\n" character since it will be present as
 			// whitespace.
 			lit = ""
+
+			// After a general comment containing a newline, the scanner
+			// reports the SEMICOLON at the position of the newline, inside
+			// the comment; move it after the comment.
+			if pos.Offset < end {
+				pos = l.file.Position(l.file.Pos(end))
+			}
 		}
+		end = pos.Offset + len(lit)
 		l.tokens <- &Token{
 			pos:   pos,
 			Code:  lit,
@@ -0,0 +1,32 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goefmt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestScan tests that Scan preserves the source code, including the white
+// space.
+func TestScan(t *testing.T) {
+	var tests = []string{
+		"package p\n\nvar x = 1\n",
+		"package p\n\nvar x = 1 // comment\n",
+		"package p\n\nvar x = 1 /* multi\n   line */\nvar y = 2\n",
+		"package p\n\nconst (\n\tA = 1 /* a\n\tb */\n\tB = 2\n)\n",
+	}
+
+	for _, src := range tests {
+		var buf []string
+		for tok := range Scan("p.go", []byte(src)) {
+			buf = append(buf, tok.String())
+		}
+		// The last token does not contain the "\n" character.
+		if got := strings.Join(buf, "") + "\n"; got != src {
+			t.Errorf("Scan(%q): got %q", src, got)
+		}
+	}
+}
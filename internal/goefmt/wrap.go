@@ -0,0 +1,101 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// wrap.go source file is responsible for wrapping long lines.
+
+package goefmt
+
+import (
+	"go/token"
+	"strings"
+	"unicode/utf8"
+)
+
+// Wrap splits the line in one or more lines, each with at most width
+// characters, breaking it at token boundaries.  Tab characters are expanded
+// using the specified tab size.  Lines with at most width characters are
+// returned unchanged.
+//
+// White space at the end of a line is not counted.  Tokens longer than width
+// are split, with each part having the same token and offset; comments are
+// split at white space, when possible.
+func Wrap(line Line, width, tabsize int) []Line {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []Line
+	cur := make(Line, 0, len(line))
+	col := 0
+	for _, span := range line {
+		code := span.Code
+		if col > 0 && col+advance(code, col, tabsize) > width {
+			lines = append(lines, cur)
+			cur = make(Line, 0, len(line))
+			col = 0
+		}
+		// Split tokens too long to fit in a line.
+		for col+advance(code, col, tabsize) > width {
+			n := fit(code, col, width, tabsize)
+			if n == 0 {
+				break
+			}
+			if span.Token == token.COMMENT {
+				if i := strings.LastIndexByte(code[:n], ' '); i > 0 {
+					n = i + 1
+				}
+			}
+			cur = append(cur, &Span{Token: span.Token, Code: code[:n], Offset: span.Offset, Class: span.Class})
+			lines = append(lines, cur)
+			cur = make(Line, 0, len(line))
+			col = 0
+			code = code[n:]
+		}
+		if code == span.Code {
+			cur = append(cur, span)
+		} else {
+			cur = append(cur, &Span{span.Token, code, span.Whitespace, span.Offset, span.Class})
+		}
+		col += advance(code, col, tabsize)
+		col += advance(span.Whitespace, col, tabsize)
+	}
+
+	return append(lines, cur)
+}
+
+// advance returns the number of columns needed to print s, starting at column
+// col.
+func advance(s string, col, tabsize int) int {
+	start := col
+	for _, r := range s {
+		col = next(r, col, tabsize)
+	}
+
+	return col - start
+}
+
+// fit returns the length in bytes of the longest prefix of s that can be
+// printed starting at column col, without exceeding width.
+func fit(s string, col, width, tabsize int) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		col = next(r, col, tabsize)
+		if col > width {
+			break
+		}
+		n += size
+	}
+
+	return n
+}
+
+// next returns the column following the rune r printed at column col.
+func next(r rune, col, tabsize int) int {
+	if r == '\t' {
+		return (col/tabsize + 1) * tabsize
+	}
+
+	return col + 1
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goefmt
+
+import (
+	"testing"
+)
+
+// format returns the last line of the formatted source code src.
+func format(src string) Line {
+	var last Line
+	for line := range Format(Scan("test.go", []byte(src))) {
+		last = line
+	}
+
+	return last
+}
+
+func TestWrap(t *testing.T) {
+	var tests = []struct {
+		src   string
+		width int
+		want  []string
+	}{
+		{"x := f(a, b)", 20, []string{"x := f(a, b)"}},
+		{"x := f(a, b)", 12, []string{"x := f(a, b)"}},
+		{"x := f(a, b)", 8, []string{"x := f(a", ", b)"}},
+		{"x := f(a, b)", 4, []string{"x := ", "f(a, ", "b)"}},
+		{"{\n\tx := y", 6, []string{"\tx ", ":= y"}},
+		{`s := "abcdefgh"`, 6, []string{"s := ", `"abcde`, `fgh"`}},
+		{"x // a comment", 8, []string{"x ", "// a ", "comment"}},
+	}
+
+	for _, test := range tests {
+		lines := Wrap(format(test.src), test.width, 4)
+		got := make([]string, len(lines))
+		for i, l := range lines {
+			got[i] = l.String()
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("Wrap(%q, %d): got %q, want %q", test.src, test.width, got, test.want)
+
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Wrap(%q, %d): got %q, want %q", test.src, test.width, got, test.want)
+
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"strings"
+)
+
+// Exports returns the export data files of the packages named by the given
+// patterns and of all their dependencies, indexed by import path.
+//
+// If test is true, Exports will also include the dependencies of the test
+// files.  Dir specifies the working directory of the go command; if it is the
+// empty string, the current directory is used.
+//
+// Packages that failed to build are not included.
+func Exports(dir string, test bool, patterns ...string) (map[string]string, error) {
+	attr := attr{
+		Dir: dir,
+	}
+	argv := []string{"-e", "-deps", "-export", "-json"}
+	if test {
+		argv = append(argv, "-test")
+	}
+	for _, pattern := range patterns {
+		if pattern != "" {
+			// Don't pass an empty argument to go list.
+			// See https://github.com/golang/go/issues/37300.
+			argv = append(argv, pattern)
+		}
+	}
+	stdout, err := invokeGo("list", argv, &attr)
+	if err != nil {
+		return nil, err
+	}
+
+	pkglist, err := decode(stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make(map[string]string, len(pkglist))
+	for _, pkg := range pkglist {
+		if pkg.Export == "" {
+			continue
+		}
+
+		// Packages recompiled for testing have an import path like
+		// "fmt [fmt.test]"; prefer the original package, if available.
+		path := pkg.ImportPath
+		if i := strings.IndexByte(path, ' '); i >= 0 {
+			path = path[:i]
+			if _, ok := exports[path]; ok {
+				continue
+			}
+		}
+		exports[path] = pkg.Export
+	}
+
+	return exports, nil
+}
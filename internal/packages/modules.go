@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,7 @@ import (
 type Module struct {
 	Path     string     // module path
 	Version  string     // module version
+	Main     bool       // is this the main module?
 	Time     *time.Time // time version was created
 	Dir      string     // directory holding files for this module, if any
 	Packages []*Package // packages belonging to the module
@@ -56,8 +59,17 @@ func (m *Module) String() string {
 
 // LoadModule loads and return the module named by pattern and all its
 // packages.
+//
+// When pattern has the form path@version, the module is not required to be in
+// the build list of the main module: it is downloaded to the module cache, if
+// necessary, using the go command configuration (e.g. GOPROXY=off or a file://
+// proxy), and can be loaded from any directory.
 func LoadModule(pattern string) (*Module, error) {
-	modlist, err := loadm(pattern)
+	load := loadm
+	if strings.Contains(pattern, "@") {
+		load = download
+	}
+	modlist, err := load(pattern)
 	if err != nil {
 		return nil, err
 	}
@@ -91,6 +103,47 @@ func loadm(pattern string) ([]*Module, error) {
 	return decodem(stdout)
 }
 
+// download downloads to the module cache and return the module version named
+// by the given pattern, with the form path@version.
+func download(pattern string) ([]*Module, error) {
+	// Run the go command outside the main module, if any, so that its go.mod
+	// and go.sum files are not used or modified.
+	attr := attr{
+		Dir: os.TempDir(),
+	}
+	argv := []string{"download", "-json", pattern}
+	stdout, err := invokeGo("mod", argv, &attr)
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		Path    string
+		Version string
+		Info    string // file with the version info
+		Dir     string
+	}
+	if err := json.NewDecoder(stdout).Decode(&info); err != nil {
+		return nil, fmt.Errorf("JSON decode: %v", err)
+	}
+	mod := &Module{
+		Path:    info.Path,
+		Version: info.Version,
+		Dir:     info.Dir,
+	}
+
+	// The time is only available in the version info file.
+	data, err := ioutil.ReadFile(info.Info)
+	if err != nil {
+		return nil, fmt.Errorf("load module %s: %v", pattern, err)
+	}
+	if err := json.Unmarshal(data, mod); err != nil {
+		return nil, fmt.Errorf("load module %s: JSON decode: %v", pattern, err)
+	}
+
+	return []*Module{mod}, nil
+}
+
 func decodem(r io.Reader) ([]*Module, error) {
 	modlist := make([]*Module, 0, 10)
 	for dec := json.NewDecoder(r); dec.More(); {
@@ -111,6 +164,11 @@ func loadPackages(mod *Module) ([]*Package, error) {
 		Dir: mod.Dir,
 	}
 	argv := []string{"-json", "./..."}
+	if !mod.Main {
+		// The dependencies of a module in the module cache may not be
+		// available, but the packages can still be printed.
+		argv = append([]string{"-e"}, argv...)
+	}
 	stdout, err := invokeGo("list", argv, &attr)
 	if err != nil {
 		return nil, err
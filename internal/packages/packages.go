@@ -20,11 +20,17 @@ type Package struct {
 	ImportPath string  // import path of package in dir
 	Name       string  // package name
 	Module     *Module // info about package's containing module, if any (can be nil)
+	Export     string  // file containing export data (when using -export)
+	Standard   bool    // is this package part of the standard Go library?
 
 	// Source files
 	GoFiles        []string // .go source files (excluding CgoFiles, TestGoFiles, XTestGoFiles)
 	CgoFiles       []string // .go sources files that import "C"
 	IgnoredGoFiles []string // .go sources ignored due to build constraints
+	CFiles         []string // .c source files
+	HFiles         []string // .h, .hh, .hpp and .hxx source files
+	SFiles         []string // .s source files
+	EmbedFiles     []string // files matched by EmbedPatterns
 
 	// Test information
 	TestGoFiles  []string // _test.go files in package
@@ -54,7 +60,7 @@ func (p *Package) TestFiles() []string {
 //
 // Load returns at least one package or an error.
 func Load(pattern string) (*Package, error) {
-	pkglist, err := load(pattern)
+	pkglist, err := load(nil, []string{pattern})
 	if err != nil {
 		return nil, err
 	}
@@ -65,13 +71,34 @@ func Load(pattern string) (*Package, error) {
 	return pkglist[0], nil
 }
 
-// load loads and return the packages named by the given pattern.
-func load(pattern string) ([]*Package, error) {
-	argv := []string{"-json"}
-	if pattern != "" {
-		// Don't pass an empty argument to go list.
-		// See https://github.com/golang/go/issues/37300.
-		argv = append(argv, pattern)
+// LoadAll loads and return all the packages named by the given patterns, in
+// the order reported by go list.  When no patterns are given, the package in
+// the current directory is loaded.
+//
+// LoadAll returns at least one package or an error.
+func LoadAll(patterns ...string) ([]*Package, error) {
+	return load(nil, patterns)
+}
+
+// LoadDeps loads and return all the packages named by the given patterns and
+// all their transitive dependencies.  Dependencies precede the packages that
+// depend on them.
+//
+// LoadDeps returns at least one package or an error.
+func LoadDeps(patterns ...string) ([]*Package, error) {
+	return load([]string{"-deps"}, patterns)
+}
+
+// load loads and return the packages named by the given patterns, invoking go
+// list with the additional flags.
+func load(flags, patterns []string) ([]*Package, error) {
+	argv := append([]string{"-json"}, flags...)
+	for _, pattern := range patterns {
+		if pattern != "" {
+			// Don't pass an empty argument to go list.
+			// See https://github.com/golang/go/issues/37300.
+			argv = append(argv, pattern)
+		}
 	}
 	stdout, err := invokeGo("list", argv, nil)
 	if err != nil {
@@ -101,6 +128,10 @@ func normalize(pkg *Package) *Package {
 	abspaths(pkg.Dir, pkg.GoFiles)
 	abspaths(pkg.Dir, pkg.CgoFiles)
 	abspaths(pkg.Dir, pkg.IgnoredGoFiles)
+	abspaths(pkg.Dir, pkg.CFiles)
+	abspaths(pkg.Dir, pkg.HFiles)
+	abspaths(pkg.Dir, pkg.SFiles)
+	abspaths(pkg.Dir, pkg.EmbedFiles)
 	abspaths(pkg.Dir, pkg.TestGoFiles)
 	abspaths(pkg.Dir, pkg.XTestGoFiles)
 
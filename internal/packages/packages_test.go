@@ -6,6 +6,7 @@ package packages
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -49,6 +50,59 @@ func TestLoadFail(t *testing.T) {
 	}
 }
 
+// TestLoadAll tests that LoadAll returns all the packages matched by the
+// patterns.
+func TestLoadAll(t *testing.T) {
+	pkglist, err := LoadAll("flag", "strings", "unicode/...")
+	if err != nil {
+		t.Fatalf("expected err == nil, got %v", err)
+	}
+	if len(pkglist) < 5 {
+		t.Fatalf("want at least 5 packages, got %d", len(pkglist))
+	}
+
+	const want = "flag"
+	if pkglist[0].ImportPath != want {
+		t.Errorf("want pkglist[0].ImportPath = %s, got %s", want, pkglist[0].ImportPath)
+	}
+}
+
+// TestLoadDeps tests that LoadDeps returns the transitive dependencies of a
+// package, before the package itself.
+func TestLoadDeps(t *testing.T) {
+	pkglist, err := LoadDeps("flag")
+	if err != nil {
+		t.Fatalf("expected err == nil, got %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, pkg := range pkglist {
+		if !pkg.Standard {
+			t.Errorf("want %s to be a standard package", pkg)
+		}
+		seen[pkg.ImportPath] = true
+	}
+	for _, want := range []string{"flag", "os", "strconv"} {
+		if !seen[want] {
+			t.Errorf("missing package %s", want)
+		}
+	}
+	if last := pkglist[len(pkglist)-1].ImportPath; last != "flag" {
+		t.Errorf("want flag as the last package, got %s", last)
+	}
+}
+
+// TestGoVersion tests that GoVersion returns the version of the go command.
+func TestGoVersion(t *testing.T) {
+	version, err := GoVersion()
+	if err != nil {
+		t.Fatalf("expected err == nil, got %v", err)
+	}
+	if !strings.HasPrefix(version, "go") {
+		t.Errorf("want a version starting with go, got %q", version)
+	}
+}
+
 // Allow changing the go command to use in the test.  This can be useful when
 // testing older versions of go, e.g.
 //  go get golang.org/dl/go1.10.8
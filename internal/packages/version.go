@@ -0,0 +1,31 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// GoVersion returns the version of the go command, as reported by go version.
+func GoVersion() (string, error) {
+	stdout, err := invokeGo("version", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadAll(stdout)
+	if err != nil {
+		return "", err
+	}
+
+	// The output has the form "go version go1.13 linux/amd64".
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 || fields[0] != "go" || fields[1] != "version" {
+		return "", fmt.Errorf("go version: unexpected output: %q", data)
+	}
+
+	return fields[2], nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Font represents a font used in a document.  It is either one of the
+// standard Type 1 fonts or an embedded TrueType font.
+type Font struct {
+	name string // PostScript name
+
+	// For embedded TrueType fonts.
+	tt   *trueType
+	used map[uint16]rune // glyphs used, mapped to the corresponding rune
+}
+
+// The Courier standard fonts.  Only the monospaced standard fonts are
+// supported, since the metrics of the other standard fonts are not available.
+var (
+	Courier            = &Font{name: "Courier"}
+	CourierBold        = &Font{name: "Courier-Bold"}
+	CourierOblique     = &Font{name: "Courier-Oblique"}
+	CourierBoldOblique = &Font{name: "Courier-BoldOblique"}
+)
+
+// ParseTrueType returns a font for embedding the TrueType font in data.
+// Fonts with CFF outlines and font collections are not supported.
+func ParseTrueType(data []byte) (*Font, error) {
+	tt, err := parseTrueType(data)
+	if err != nil {
+		return nil, err
+	}
+	f := &Font{
+		name: tt.name,
+		tt:   tt,
+		used: make(map[uint16]rune),
+	}
+
+	return f, nil
+}
+
+// Name returns the PostScript name of the font.
+func (f *Font) Name() string {
+	return f.name
+}
+
+// Width returns the width of the string s, in thousandths of the font size.
+func (f *Font) Width(s string) float64 {
+	if f.tt == nil {
+		// All the glyphs in the Courier fonts have the same width.
+		return float64(600 * len([]rune(s)))
+	}
+
+	w := 0.0
+	for _, r := range s {
+		w += f.tt.width(f.tt.glyph(r))
+	}
+
+	return w
+}
+
+// encode returns the PDF string for drawing s with the font f.
+func (f *Font) encode(s string) string {
+	if f.tt == nil {
+		return "(" + escape(winAnsi(s)) + ")"
+	}
+
+	var buf strings.Builder
+
+	buf.WriteByte('<')
+	for _, r := range s {
+		gid := f.tt.glyph(r)
+		if _, ok := f.used[gid]; !ok {
+			f.used[gid] = r
+		}
+		fmt.Fprintf(&buf, "%04X", gid)
+	}
+	buf.WriteByte('>')
+
+	return buf.String()
+}
+
+// objects returns the number of objects needed to write the font.
+func (f *Font) objects() int {
+	if f.tt == nil {
+		return 1
+	}
+
+	// Type0 font, CIDFont, font descriptor, font file and ToUnicode CMap.
+	return 5
+}
+
+// write writes the font objects, starting at id.
+func (f *Font) write(w *writer, id int) {
+	if f.tt == nil {
+		w.begin(id)
+		w.printf("<< /Type /Font /Subtype /Type1 /BaseFont /%s "+
+			"/Encoding /WinAnsiEncoding >>", f.name)
+		w.end()
+
+		return
+	}
+
+	tt := f.tt
+	scale := func(v int) string {
+		return number(float64(v) * 1000 / float64(tt.unitsPerEm))
+	}
+	gids := make([]int, 0, len(f.used))
+	for gid := range f.used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	w.begin(id)
+	w.printf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", f.name, id+1, id+4)
+	w.end()
+
+	widths := make([]string, len(gids))
+	for i, gid := range gids {
+		widths[i] = fmt.Sprintf("%d [%s]", gid, number(tt.width(uint16(gid))))
+	}
+	w.begin(id + 1)
+	w.printf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /DW %s\n/W [%s] >>",
+		f.name, id+2, number(tt.width(0)), strings.Join(widths, " "))
+	w.end()
+
+	flags := 32 // nonsymbolic
+	if tt.fixedPitch {
+		flags |= 1
+	}
+	w.begin(id + 2)
+	w.printf("<< /Type /FontDescriptor /FontName /%s /Flags %d "+
+		"/FontBBox [%s %s %s %s] /ItalicAngle %s /Ascent %s /Descent %s "+
+		"/CapHeight %s /StemV 80 /FontFile2 %d 0 R >>", f.name, flags,
+		scale(tt.bbox[0]), scale(tt.bbox[1]), scale(tt.bbox[2]), scale(tt.bbox[3]),
+		number(tt.italicAngle), scale(tt.ascent), scale(tt.descent),
+		scale(tt.capHeight), id+3)
+	w.end()
+
+	w.begin(id + 3)
+	w.stream(fmt.Sprintf("/Length1 %d", len(tt.data)), tt.data)
+	w.end()
+
+	w.begin(id + 4)
+	w.stream("", toUnicode(f.used, gids))
+	w.end()
+}
+
+// toUnicode returns a ToUnicode CMap mapping the glyphs to the runes in used.
+func toUnicode(used map[uint16]rune, gids []int) []byte {
+	var buf strings.Builder
+
+	buf.WriteString("/CIDInit /ProcSet findresource begin\n" +
+		"12 dict begin\n" +
+		"begincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n" +
+		"/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// At most 100 entries are allowed in each section.
+	for len(gids) > 0 {
+		n := len(gids)
+		if n > 100 {
+			n = 100
+		}
+		fmt.Fprintf(&buf, "%d beginbfchar\n", n)
+		for _, gid := range gids[:n] {
+			r := used[uint16(gid)]
+			fmt.Fprintf(&buf, "<%04X> <%s>\n", gid, utf16hex(string(r)))
+		}
+		buf.WriteString("endbfchar\n")
+		gids = gids[n:]
+	}
+
+	buf.WriteString("endcmap\n" +
+		"CMapName currentdict /CMap defineresource pop\n" +
+		"end\n" +
+		"end\n")
+
+	return []byte(buf.String())
+}
+
+// utf16hex returns the hexadecimal representation of s encoded as UTF-16BE.
+func utf16hex(s string) string {
+	var buf strings.Builder
+
+	for _, c := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&buf, "%04X", c)
+	}
+
+	return buf.String()
+}
+
+// Characters in the range 0x80-0x9F of the WinAnsiEncoding.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86,
+	'‡': 0x87, 'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C,
+	'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsi encodes s using the WinAnsiEncoding.  Characters that can not be
+// encoded are replaced with '?'.
+func winAnsi(s string) string {
+	buf := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			buf = append(buf, byte(r))
+		case winAnsiExtra[r] != 0:
+			buf = append(buf, winAnsiExtra[r])
+		default:
+			buf = append(buf, '?')
+		}
+	}
+
+	return string(buf)
+}
@@ -0,0 +1,443 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pdf implements a minimal PDF writer, suitable for documents
+// consisting only of text, lines, rectangles and internal links.
+//
+// The generated documents conform to PDF 1.4.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Color represents an RGB color.  Each component is in the range [0, 1].
+type Color struct {
+	R, G, B float64
+}
+
+// Black is the default color.
+var Black = Color{0, 0, 0}
+
+// Gray returns the gray color with the specified level.
+func Gray(level float64) Color {
+	return Color{level, level, level}
+}
+
+// String returns the color as operands of a PDF color operator.
+func (c Color) String() string {
+	return fmt.Sprintf("%s %s %s", number(c.R), number(c.G), number(c.B))
+}
+
+// TextStyle represents the style used to draw text.
+type TextStyle struct {
+	Font  *Font
+	Size  float64 // font size, in points
+	Color Color
+
+	// Synthetic styles, to use when a font does not have the bold or italic
+	// variant.
+	Bold   bool
+	Italic bool
+}
+
+// Document represents a PDF document.  All the pages have the same size.
+type Document struct {
+	Width  float64 // page width, in points
+	Height float64 // page height, in points
+
+	// Document information, optional.
+	Title   string
+	Author  string
+	Creator string
+	Date    time.Time
+
+	pages []*Page
+}
+
+// New returns a new empty document, with the specified page size in points.
+func New(width, height float64) *Document {
+	d := &Document{
+		Width:  width,
+		Height: height,
+	}
+
+	return d
+}
+
+// AddPage adds a new page at the end of the document, and returns it.
+func (d *Document) AddPage() *Page {
+	p := &Page{
+		doc:   d,
+		dests: make(map[string]float64),
+	}
+	d.pages = append(d.pages, p)
+
+	return p
+}
+
+// link represents an internal link.
+type link struct {
+	x, y, w, h float64 // in PDF user space
+	dest       string
+}
+
+// Page represents a page in a document.
+//
+// Coordinates are in points, with the origin at the top left corner of the
+// page and with y increasing downward.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	fonts   []*Font // fonts used in the page
+	links   []link
+	dests   map[string]float64
+}
+
+// font returns the resource name for font f, adding it to the page resources
+// if necessary.
+func (p *Page) font(f *Font) string {
+	for i, ent := range p.fonts {
+		if ent == f {
+			return fmt.Sprintf("F%d", i+1)
+		}
+	}
+	p.fonts = append(p.fonts, f)
+
+	return fmt.Sprintf("F%d", len(p.fonts))
+}
+
+// Text draws the string s with the specified style, starting at (x, y), where
+// y is the position of the baseline.  It returns the width of the text.
+func (p *Page) Text(x, y float64, s string, style TextStyle) float64 {
+	if s == "" {
+		return 0
+	}
+	buf := &p.content
+	name := p.font(style.Font)
+	skew := 0.0
+	if style.Italic {
+		skew = 0.2
+	}
+
+	fmt.Fprintf(buf, "BT /%s %s Tf %s rg ", name, number(style.Size), style.Color)
+	if style.Bold {
+		// Stroke the glyphs outline.
+		fmt.Fprintf(buf, "2 Tr %s w %s RG ", number(style.Size/30), style.Color)
+	} else {
+		buf.WriteString("0 Tr ")
+	}
+	fmt.Fprintf(buf, "1 0 %s 1 %s %s Tm %s Tj ET\n", number(skew), number(x),
+		number(p.doc.Height-y), style.Font.encode(s))
+
+	return style.Font.Width(s) * style.Size / 1000
+}
+
+// Line draws a line from (x1, y1) to (x2, y2), with the specified width and
+// color.
+func (p *Page) Line(x1, y1, x2, y2, width float64, c Color) {
+	h := p.doc.Height
+	fmt.Fprintf(&p.content, "%s w %s RG %s %s m %s %s l S\n", number(width), c,
+		number(x1), number(h-y1), number(x2), number(h-y2))
+}
+
+// Rect fills the rectangle with the top left corner at (x, y) and with the
+// specified width and height, using color c.
+func (p *Page) Rect(x, y, w, h float64, c Color) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n", c, number(x),
+		number(p.doc.Height-y-h), number(w), number(h))
+}
+
+// Link adds an internal link to the named destination dest, activated by the
+// rectangle with the top left corner at (x, y) and with the specified width
+// and height.
+//
+// Links to destinations not defined in the document are ignored.
+func (p *Page) Link(x, y, w, h float64, dest string) {
+	l := link{
+		x:    x,
+		y:    p.doc.Height - y - h,
+		w:    w,
+		h:    h,
+		dest: dest,
+	}
+	p.links = append(p.links, l)
+}
+
+// Dest defines the named destination name, at the vertical position y in the
+// page.
+func (p *Page) Dest(name string, y float64) {
+	p.dests[name] = p.doc.Height - y
+}
+
+// WriteTo implements the WriterTo interface.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pw := newWriter()
+
+	// Collect the fonts and named destinations.
+	var fonts []*Font
+	fontID := make(map[*Font]int)
+	dests := make(map[string]bool)
+	for _, page := range d.pages {
+		for _, f := range page.fonts {
+			if _, ok := fontID[f]; !ok {
+				fontID[f] = 0
+				fonts = append(fonts, f)
+			}
+		}
+		for name := range page.dests {
+			dests[name] = true
+		}
+	}
+
+	// Allocate object numbers.
+	const (
+		catalogID = 1
+		pagesID   = 2
+		infoID    = 3
+	)
+	next := 4
+	for _, f := range fonts {
+		fontID[f] = next
+		next += f.objects()
+	}
+	pageID := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageID[i] = next
+		next += 2 // page and content stream
+	}
+
+	// Catalog.
+	pw.begin(catalogID)
+	pw.printf("<< /Type /Catalog /Pages %d 0 R", pagesID)
+	if len(dests) > 0 {
+		names := make([]string, 0, len(dests))
+		for name := range dests {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		pw.printf("\n/Dests <<")
+		for _, name := range names {
+			for i, page := range d.pages {
+				if y, ok := page.dests[name]; ok {
+					pw.printf("\n%s [%d 0 R /XYZ null %s null]", pdfname(name),
+						pageID[i], number(y))
+
+					break
+				}
+			}
+		}
+		pw.printf("\n>>")
+	}
+	pw.printf(" >>")
+	pw.end()
+
+	// Pages.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageID[i])
+	}
+	pw.begin(pagesID)
+	pw.printf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(d.pages), number(d.Width), number(d.Height))
+	pw.end()
+
+	// Document information.
+	pw.begin(infoID)
+	pw.printf("<<")
+	if d.Title != "" {
+		pw.printf(" /Title %s", pdfstring(d.Title))
+	}
+	if d.Author != "" {
+		pw.printf(" /Author %s", pdfstring(d.Author))
+	}
+	if d.Creator != "" {
+		pw.printf(" /Creator %s", pdfstring(d.Creator))
+	}
+	if !d.Date.IsZero() {
+		date := d.Date.UTC().Format("D:20060102150405Z")
+		pw.printf(" /CreationDate %s", pdfstring(date))
+	}
+	pw.printf(" >>")
+	pw.end()
+
+	// Fonts.
+	for _, f := range fonts {
+		f.write(pw, fontID[f])
+	}
+
+	// Pages.
+	for i, page := range d.pages {
+		id := pageID[i]
+		pw.begin(id)
+		pw.printf("<< /Type /Page /Parent %d 0 R /Contents %d 0 R", pagesID, id+1)
+		pw.printf("\n/Resources << /ProcSet [/PDF /Text] /Font <<")
+		for j, f := range page.fonts {
+			pw.printf(" /F%d %d 0 R", j+1, fontID[f])
+		}
+		pw.printf(" >> >>")
+		annots := make([]string, 0, len(page.links))
+		for _, l := range page.links {
+			if !dests[l.dest] {
+				continue
+			}
+			annot := fmt.Sprintf("<< /Type /Annot /Subtype /Link /Border [0 0 0] "+
+				"/Rect [%s %s %s %s] /Dest %s >>", number(l.x), number(l.y),
+				number(l.x+l.w), number(l.y+l.h), pdfname(l.dest))
+			annots = append(annots, annot)
+		}
+		if len(annots) > 0 {
+			pw.printf("\n/Annots [\n%s\n]", strings.Join(annots, "\n"))
+		}
+		pw.printf(" >>")
+		pw.end()
+
+		pw.begin(id + 1)
+		pw.stream("", page.content.Bytes())
+		pw.end()
+	}
+
+	pw.trailer(catalogID, infoID)
+
+	return io.Copy(w, &pw.buf)
+}
+
+// writer is used to serialize PDF objects, keeping track of their offsets.
+type writer struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func newWriter() *writer {
+	w := &writer{
+		offsets: make(map[int]int),
+	}
+	// Add a comment with binary characters, as recommended by the PDF
+	// specification.
+	w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	return w
+}
+
+func (w *writer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+// begin starts the object with the specified number.
+func (w *writer) begin(id int) {
+	w.offsets[id] = w.buf.Len()
+	w.printf("%d 0 obj\n", id)
+}
+
+// end ends the current object.
+func (w *writer) end() {
+	w.printf("\nendobj\n")
+}
+
+// stream writes a stream object, compressed with the Flate filter.  dict
+// contains additional entries for the stream dictionary.
+func (w *writer) stream(dict string, data []byte) {
+	var buf bytes.Buffer
+
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data) // a bytes.Buffer never fails
+	zw.Close()
+
+	if dict != "" {
+		dict = " " + dict
+	}
+	w.printf("<< /Length %d /Filter /FlateDecode%s >>\nstream\n", buf.Len(), dict)
+	w.buf.Write(buf.Bytes())
+	w.printf("\nendstream")
+}
+
+// trailer writes the cross reference table and the trailer.
+func (w *writer) trailer(root, info int) {
+	size := 0
+	for id := range w.offsets {
+		if id > size {
+			size = id
+		}
+	}
+	size++
+
+	xref := w.buf.Len()
+	w.printf("xref\n0 %d\n", size)
+	w.printf("0000000000 65535 f\r\n")
+	for id := 1; id < size; id++ {
+		w.printf("%010d 00000 n\r\n", w.offsets[id])
+	}
+	w.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\n", size, root, info)
+	w.printf("startxref\n%d\n%%%%EOF\n", xref)
+}
+
+// number returns the PDF representation of a real number, with at most 3
+// decimal digits.
+func number(v float64) string {
+	s := fmt.Sprintf("%.3f", v)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		s = "0"
+	}
+
+	return s
+}
+
+// pdfname returns the PDF representation of a name object.
+func pdfname(s string) string {
+	var buf strings.Builder
+
+	buf.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '!' || c > '~' || strings.IndexByte("#%()/<>[]{}", c) >= 0 {
+			fmt.Fprintf(&buf, "#%02X", c)
+
+			continue
+		}
+		buf.WriteByte(c)
+	}
+
+	return buf.String()
+}
+
+// pdfstring returns the PDF representation of a text string.  Strings that
+// are not ASCII are encoded as UTF-16BE.
+func pdfstring(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return "<FEFF" + utf16hex(s) + ">"
+		}
+	}
+
+	return "(" + escape(s) + ")"
+}
+
+// escape escapes the special characters in a PDF literal string.
+func escape(s string) string {
+	var buf strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < ' ' || c >= 0x7f:
+			fmt.Fprintf(&buf, "\\%03o", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// TestDocument tests that a document is correctly serialized, checking the
+// cross reference table.
+func TestDocument(t *testing.T) {
+	doc := New(595, 842)
+	style := TextStyle{Font: Courier, Size: 10}
+
+	p1 := doc.AddPage()
+	p1.Text(72, 72, "hello (world)", style)
+	p1.Link(72, 62, 100, 12, "target")
+	p1.Link(72, 82, 100, 12, "missing")
+	p2 := doc.AddPage()
+	p2.Dest("target", 72)
+	style.Font = CourierBold
+	p2.Text(72, 72, "func", style)
+
+	buf := new(bytes.Buffer)
+	if _, err := doc.WriteTo(buf); err != nil {
+		t.Fatalf("expected err == nil, got %v", err)
+	}
+	data := buf.Bytes()
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+		t.Error("missing PDF header")
+	}
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Error("missing PDF trailer")
+	}
+
+	// Check that each entry in the cross reference table points to the
+	// correct object.
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	var size int
+	if _, err := fmt.Sscanf(string(data[xref:]), "xref\n0 %d\n", &size); err != nil {
+		t.Fatalf("invalid xref table: %v", err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n\r\n`).FindAllSubmatch(data[xref:], -1)
+	if len(entries) != size-1 {
+		t.Fatalf("got %d xref entries, want %d", len(entries), size-1)
+	}
+	for i, ent := range entries {
+		off, _ := strconv.Atoi(string(ent[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(data[off:], []byte(want)) {
+			t.Errorf("object %d: invalid offset %d", i+1, off)
+		}
+	}
+
+	// Only the link to a defined destination is written.
+	if n := bytes.Count(data, []byte("/Subtype /Link")); n != 1 {
+		t.Errorf("got %d links, want 1", n)
+	}
+}
+
+// TestWinAnsi tests the conversion of strings to the WinAnsiEncoding.
+func TestWinAnsi(t *testing.T) {
+	var tests = []struct {
+		input  string
+		output string
+	}{
+		{"", ""},
+		{"func", "func"},
+		{"café", "caf\xe9"},
+		{"€", "\x80"},
+		{"→", "?"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			output := winAnsi(test.input)
+			if output != test.output {
+				t.Errorf("got %q, want %q", output, test.output)
+			}
+		})
+	}
+}
+
+// TestInvalidTrueType tests that ParseTrueType returns an error when an
+// invalid font is provided.
+func TestInvalidTrueType(t *testing.T) {
+	var tests = []string{
+		"", "\x00\x01", "OTTO\x00\x00", "\x00\x01\x00\x00\x00\x01",
+		"\x00\x01\x00\x00\x00\x00",
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%q", test), func(t *testing.T) {
+			f, err := ParseTrueType([]byte(test))
+			if err == nil {
+				t.Errorf("expected err != nil, got f == %v", f.Name())
+			}
+		})
+	}
+}
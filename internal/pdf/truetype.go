@@ -0,0 +1,290 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Support for TrueType fonts, as specified in
+//
+//	OpenType Specification Version 1.9
+//
+// Only the tables required for embedding a font in a PDF document are
+// parsed.
+
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// trueType represents a parsed TrueType font.
+type trueType struct {
+	data []byte
+	name string // PostScript name
+
+	unitsPerEm  int
+	bbox        [4]int // xMin, yMin, xMax, yMax
+	ascent      int
+	descent     int
+	capHeight   int
+	italicAngle float64
+	fixedPitch  bool
+	advances    []int // advance width of each glyph
+	cmap        map[rune]uint16
+}
+
+// glyph returns the glyph index for the rune r, or 0 (the missing glyph).
+func (tt *trueType) glyph(r rune) uint16 {
+	return tt.cmap[r]
+}
+
+// width returns the advance width of the glyph gid, in thousandths of the
+// font size.
+func (tt *trueType) width(gid uint16) float64 {
+	if len(tt.advances) == 0 {
+		return 0
+	}
+	adv := tt.advances[len(tt.advances)-1]
+	if int(gid) < len(tt.advances) {
+		adv = tt.advances[gid]
+	}
+
+	return float64(adv) * 1000 / float64(tt.unitsPerEm)
+}
+
+var errInvalidFont = errors.New("invalid TrueType font")
+
+// table represents a slice of the font data.
+type table []byte
+
+func (t table) u16(off int) int {
+	if off < 0 || off+2 > len(t) {
+		panic(errInvalidFont)
+	}
+
+	return int(binary.BigEndian.Uint16(t[off:]))
+}
+
+func (t table) i16(off int) int {
+	return int(int16(t.u16(off)))
+}
+
+func (t table) u32(off int) int {
+	if off < 0 || off+4 > len(t) {
+		panic(errInvalidFont)
+	}
+
+	return int(binary.BigEndian.Uint32(t[off:]))
+}
+
+func (t table) slice(off, n int) table {
+	if off < 0 || n < 0 || off+n > len(t) {
+		panic(errInvalidFont)
+	}
+
+	return t[off : off+n]
+}
+
+// parseTrueType parses the TrueType font in data.
+func parseTrueType(data []byte) (tt *trueType, err error) {
+	// The table accessors panic on out of bounds access.
+	defer func() {
+		if v := recover(); v != nil {
+			if v != errInvalidFont {
+				panic(v)
+			}
+			tt = nil
+			err = errInvalidFont
+		}
+	}()
+
+	font := table(data)
+	switch font.u32(0) {
+	case 0x00010000, 0x74727565: // 1.0, "true"
+	case 0x4F54544F: // "OTTO"
+		return nil, errors.New("unsupported font: CFF outlines")
+	case 0x74746366: // "ttcf"
+		return nil, errors.New("unsupported font: font collection")
+	default:
+		return nil, errInvalidFont
+	}
+
+	tables := make(map[string]table)
+	n := font.u16(4)
+	for i := 0; i < n; i++ {
+		rec := font.slice(12+16*i, 16)
+		tag := string(rec[:4])
+		tables[tag] = font.slice(rec.u32(8), rec.u32(12))
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "glyf"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("invalid TrueType font: missing %q table", tag)
+		}
+	}
+
+	tt = &trueType{
+		data: data,
+		name: "TrueTypeFont",
+	}
+
+	head := tables["head"]
+	tt.unitsPerEm = head.u16(18)
+	if tt.unitsPerEm == 0 {
+		return nil, errInvalidFont
+	}
+	tt.bbox = [4]int{head.i16(36), head.i16(38), head.i16(40), head.i16(42)}
+
+	hhea := tables["hhea"]
+	tt.ascent = hhea.i16(4)
+	tt.descent = hhea.i16(6)
+	tt.capHeight = tt.ascent
+	nmetrics := hhea.u16(34)
+
+	numGlyphs := tables["maxp"].u16(4)
+	hmtx := tables["hmtx"]
+	if nmetrics > numGlyphs {
+		nmetrics = numGlyphs
+	}
+	tt.advances = make([]int, nmetrics)
+	for i := range tt.advances {
+		tt.advances[i] = hmtx.u16(4 * i)
+	}
+
+	if post, ok := tables["post"]; ok {
+		tt.italicAngle = float64(int32(post.u32(4))) / 65536
+		tt.fixedPitch = post.u32(12) != 0
+	}
+	if os2, ok := tables["OS/2"]; ok && os2.u16(0) >= 2 {
+		tt.capHeight = os2.i16(88)
+	}
+	if name, ok := tables["name"]; ok {
+		if s := psname(name); s != "" {
+			tt.name = s
+		}
+	}
+
+	cmap, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	tt.cmap = cmap
+
+	return tt, nil
+}
+
+// psname returns the PostScript name in the name table, or an empty string.
+func psname(name table) string {
+	const postScriptName = 6
+
+	count := name.u16(2)
+	storage := name.u16(4)
+	for i := 0; i < count; i++ {
+		rec := name.slice(6+12*i, 12)
+		if rec.u16(6) != postScriptName {
+			continue
+		}
+		str := name.slice(storage+rec.u16(10), rec.u16(8))
+
+		var s string
+		switch rec.u16(0) {
+		case 0, 3: // Unicode and Windows, UTF-16BE
+			u := make([]uint16, len(str)/2)
+			for j := range u {
+				u[j] = uint16(str.u16(2 * j))
+			}
+			s = string(utf16.Decode(u))
+		case 1: // Macintosh, assume ASCII
+			s = string(str)
+		default:
+			continue
+		}
+
+		// Ensure the name is a valid PDF name, without white space.
+		valid := func(r rune) rune {
+			if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+				return -1
+			}
+
+			return r
+		}
+		if s = strings.Map(valid, s); s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// parseCmap parses the cmap table, using the first Unicode subtable with
+// format 4 or 12.
+func parseCmap(cmap table) (map[rune]uint16, error) {
+	var sub4, sub12 table
+
+	n := cmap.u16(2)
+	for i := 0; i < n; i++ {
+		rec := cmap.slice(4+8*i, 8)
+		platform, encoding := rec.u16(0), rec.u16(2)
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode {
+			continue
+		}
+		off := rec.u32(4)
+		sub := cmap.slice(off, len(cmap)-off)
+		switch sub.u16(0) {
+		case 4:
+			if sub4 == nil {
+				sub4 = sub
+			}
+		case 12:
+			if sub12 == nil {
+				sub12 = sub
+			}
+		}
+	}
+
+	m := make(map[rune]uint16)
+	switch {
+	case sub12 != nil:
+		ngroups := sub12.u32(12)
+		for i := 0; i < ngroups; i++ {
+			group := sub12.slice(16+12*i, 12)
+			start, end, gid := group.u32(0), group.u32(4), group.u32(8)
+			if end < start || end > 0x10FFFF {
+				return nil, errInvalidFont
+			}
+			for r := start; r <= end; r++ {
+				m[rune(r)] = uint16(gid + r - start)
+			}
+		}
+	case sub4 != nil:
+		segs := sub4.u16(6) / 2
+		ends := 14
+		starts := ends + 2*segs + 2
+		deltas := starts + 2*segs
+		offsets := deltas + 2*segs
+		for i := 0; i < segs; i++ {
+			start, end := sub4.u16(starts+2*i), sub4.u16(ends+2*i)
+			delta, off := sub4.u16(deltas+2*i), sub4.u16(offsets+2*i)
+			for r := start; r <= end && r != 0xFFFF; r++ {
+				var gid int
+				if off == 0 {
+					gid = r + delta
+				} else {
+					gid = sub4.u16(offsets + 2*i + off + 2*(r-start))
+					if gid != 0 {
+						gid += delta
+					}
+				}
+				if gid&0xFFFF != 0 {
+					m[rune(r)] = uint16(gid)
+				}
+			}
+		}
+	default:
+		return nil, errors.New("unsupported font: no Unicode cmap")
+	}
+
+	return m, nil
+}
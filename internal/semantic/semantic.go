@@ -0,0 +1,353 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package semantic implements the classification of Go identifiers, using the
+// information computed by the go/types package.
+//
+// The classification is best effort: type errors are ignored and identifiers
+// that can not be resolved are reported as Unknown.
+package semantic
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+)
+
+// Kind represents the kind of the entity denoted by an identifier.
+type Kind int
+
+// Supported kinds.
+const (
+	Unknown Kind = iota
+	PackageName
+	TypeName
+	Func
+	Method
+	Field
+	Const
+	Var
+	Param
+	Label
+)
+
+var kinds = [...]string{
+	Unknown:     "",
+	PackageName: "pkgname",
+	TypeName:    "type",
+	Func:        "func",
+	Method:      "method",
+	Field:       "field",
+	Const:       "const",
+	Var:         "var",
+	Param:       "param",
+	Label:       "label",
+}
+
+// String implements the Stringer interface.  The returned string is suitable
+// to be used as an HTML class.
+func (k Kind) String() string {
+	return kinds[k]
+}
+
+// Ident describes a classified identifier.
+type Ident struct {
+	Kind    Kind
+	Builtin bool // the identifier denotes a predeclared entity
+	Decl    bool // the identifier declares the entity
+}
+
+// Class returns the HTML class for the identifier.  It is a refinement of the
+// class returned by goefmt.TokenClass.
+func (id Ident) Class() []string {
+	// Avoid extra allocation.
+	class := make([]string, 0, 4)
+
+	class = append(class, "ident")
+	if id.Kind != Unknown {
+		class = append(class, id.Kind.String())
+	}
+	if id.Builtin {
+		class = append(class, "builtin")
+	}
+	if id.Decl {
+		class = append(class, "decl")
+	}
+
+	return class
+}
+
+// Source describes the Go source files of a package to type check.
+type Source struct {
+	ImportPath string
+	Name       string   // package name
+	Files      []string // absolute paths of the source files
+}
+
+// key is used to index an identifier in a source file.
+type key struct {
+	filename string
+	offset   int
+}
+
+// Program is a collection of type checked packages.
+type Program struct {
+	fset    *token.FileSet
+	idents  map[key]Ident
+	decls   map[key]key // identifier to declaration of the entity
+	sources map[string]*Source
+	pkgs    map[string]*types.Package
+	checked map[*types.Package]bool // packages checked from source
+	gcimp   types.Importer
+}
+
+// Load type checks the packages in srcs.
+//
+// Imports of packages in srcs are resolved from source code.  All the other
+// imports are resolved using the export data files in exports, indexed by
+// import path.
+//
+// Files of a package having a package name different from Source.Name, like
+// external test files, are checked as a separate package.
+func Load(srcs []*Source, exports map[string]string) *Program {
+	p := &Program{
+		fset:    token.NewFileSet(),
+		idents:  make(map[key]Ident),
+		decls:   make(map[key]key),
+		sources: make(map[string]*Source, len(srcs)),
+		pkgs:    make(map[string]*types.Package),
+		checked: make(map[*types.Package]bool),
+	}
+	lookup := func(path string) (io.ReadCloser, error) {
+		filename, ok := exports[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+
+		return os.Open(filename)
+	}
+	p.gcimp = importer.ForCompiler(p.fset, "gc", lookup)
+
+	for _, src := range srcs {
+		p.sources[src.ImportPath] = src
+	}
+	for _, src := range srcs {
+		p.load(src)
+	}
+
+	return p
+}
+
+// Lookup returns the identifier at the specified offset of the source file
+// named by filename.
+func (p *Program) Lookup(filename string, offset int) (Ident, bool) {
+	id, ok := p.idents[key{filename, offset}]
+
+	return id, ok
+}
+
+// Decl returns the position of the declaration of the entity denoted by the
+// identifier at the specified offset of the source file named by filename.
+//
+// Only package level entities, methods and struct fields declared in the
+// packages checked from source are reported.
+func (p *Program) Decl(filename string, offset int) (token.Position, bool) {
+	k, ok := p.decls[key{filename, offset}]
+	if !ok {
+		return token.Position{}, false
+	}
+	pos := token.Position{
+		Filename: k.filename,
+		Offset:   k.offset,
+	}
+
+	return pos, true
+}
+
+// Import implements the types.Importer interface.
+func (p *Program) Import(path string) (*types.Package, error) {
+	if pkg, ok := p.pkgs[path]; ok {
+		return pkg, nil
+	}
+	if src, ok := p.sources[path]; ok {
+		return p.load(src), nil
+	}
+
+	return p.gcimp.Import(path)
+}
+
+// load type checks the package described by src, if not already done.
+func (p *Program) load(src *Source) *types.Package {
+	if pkg, ok := p.pkgs[src.ImportPath]; ok {
+		return pkg
+	}
+
+	// Group files by package name; errors are ignored.
+	var names []string
+	groups := make(map[string][]*ast.File)
+	for _, filename := range src.Files {
+		f, _ := parser.ParseFile(p.fset, filename, nil, parser.ParseComments)
+		if f == nil || f.Name == nil {
+			continue
+		}
+		name := f.Name.Name
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], f)
+	}
+
+	// Register the package before checking it, in order to handle import
+	// cycles.
+	pkg := types.NewPackage(src.ImportPath, src.Name)
+	p.pkgs[src.ImportPath] = pkg
+	p.check(pkg, groups[src.Name])
+	for _, name := range names {
+		if name == src.Name {
+			continue
+		}
+		// Use a path that can not be imported.
+		path := src.ImportPath + " [" + name + "]"
+		p.check(types.NewPackage(path, name), groups[name])
+	}
+
+	return pkg
+}
+
+// check type checks the files of package pkg and classifies all their
+// identifiers.
+func (p *Program) check(pkg *types.Package, files []*ast.File) {
+	conf := types.Config{
+		Importer:    p,
+		FakeImportC: true,
+		Error:       func(error) {}, // ignore errors
+	}
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	check := types.NewChecker(&conf, p.fset, pkg, info)
+	check.Files(files) // ignore errors
+	p.checked[pkg] = true
+
+	// Collect the parameters of all functions, since types.Var does not
+	// report if it is a parameter.
+	params := make(map[types.Object]bool)
+	addParams := func(list *ast.FieldList) {
+		if list == nil {
+			return
+		}
+		for _, field := range list.List {
+			for _, name := range field.Names {
+				if obj := info.Defs[name]; obj != nil {
+					params[obj] = true
+				}
+			}
+		}
+	}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				addParams(n.Recv)
+			case *ast.FuncType:
+				addParams(n.Params)
+				addParams(n.Results)
+			}
+
+			return true
+		})
+	}
+
+	for _, f := range files {
+		// The package name in the package clause has no associated object.
+		p.record(f.Name, nil, Ident{Kind: PackageName})
+	}
+	for ident, obj := range info.Defs {
+		if obj == nil {
+			continue
+		}
+		p.record(ident, obj, classify(obj, params, true))
+	}
+	for ident, obj := range info.Uses {
+		// For an embedded field, Uses returns the type name, that takes
+		// precedence over the field returned by Defs.
+		p.record(ident, obj, classify(obj, params, false))
+	}
+}
+
+// record records the classification id for ident, and the declaration of the
+// entity obj it denotes, if any.
+func (p *Program) record(ident *ast.Ident, obj types.Object, id Ident) {
+	pos := p.fset.Position(ident.Pos())
+	k := key{pos.Filename, pos.Offset}
+	p.idents[k] = id
+	if obj == nil || !p.linkable(obj) {
+		return
+	}
+
+	pos = p.fset.Position(obj.Pos())
+	p.decls[k] = key{pos.Filename, pos.Offset}
+}
+
+// linkable returns true if obj is a package level entity, a method or a struct
+// field declared in a package checked from source.
+func (p *Program) linkable(obj types.Object) bool {
+	pkg := obj.Pkg()
+	if pkg == nil || !p.checked[pkg] || !obj.Pos().IsValid() {
+		return false
+	}
+	switch obj := obj.(type) {
+	case *types.Func:
+		return true
+	case *types.Var:
+		if obj.IsField() {
+			return true
+		}
+	}
+
+	return obj.Parent() == pkg.Scope()
+}
+
+// classify returns the classification of an identifier denoting obj.
+func classify(obj types.Object, params map[types.Object]bool, decl bool) Ident {
+	id := Ident{
+		Builtin: obj.Parent() == types.Universe,
+		Decl:    decl,
+	}
+	switch obj := obj.(type) {
+	case *types.PkgName:
+		id.Kind = PackageName
+	case *types.TypeName:
+		id.Kind = TypeName
+	case *types.Func:
+		id.Kind = Func
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+			id.Kind = Method
+		}
+	case *types.Builtin:
+		id.Kind = Func
+	case *types.Var:
+		switch {
+		case obj.IsField():
+			id.Kind = Field
+		case params[obj]:
+			id.Kind = Param
+		default:
+			id.Kind = Var
+		}
+	case *types.Nil:
+		id.Kind = Var
+	case *types.Const:
+		id.Kind = Const
+	case *types.Label:
+		id.Kind = Label
+	}
+
+	return id
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package semantic
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoad tests that identifiers are correctly classified, including
+// shadowed builtins.  Imported packages are not available, since no export
+// data is provided.
+func TestLoad(t *testing.T) {
+	path, err := filepath.Abs("testdata/shadow.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &Source{
+		ImportPath: "shadow",
+		Name:       "shadow",
+		Files:      []string{path},
+	}
+	prog := Load([]*Source{src}, nil)
+
+	var tests = []struct {
+		code string // code to search, identifier is at the start
+		n    int    // n-th occurrence of code
+		id   Ident
+	}{
+		{"shadow", 1, Ident{Kind: PackageName}},
+		{"T struct", 1, Ident{Kind: TypeName, Decl: true}},
+		{"Name string", 1, Ident{Kind: Field, Decl: true}},
+		{"t *T", 1, Ident{Kind: Param, Decl: true}},
+		{"Len()", 1, Ident{Kind: Method, Decl: true}},
+		{"int {", 1, Ident{Kind: TypeName, Builtin: true}},
+		{"len :=", 1, Ident{Kind: Var, Decl: true}},
+		{"strings.", 1, Ident{Kind: PackageName}},
+		{"Name,", 1, Ident{Kind: Field}},
+		{"len\n", 1, Ident{Kind: Var}},
+		{"len int", 1, Ident{Kind: Param, Decl: true}},
+		{"Loop:", 1, Ident{Kind: Label, Decl: true}},
+		{"Loop\n", 1, Ident{Kind: Label}},
+		{"len\n", 2, Ident{Kind: Param}},
+		{"C =", 1, Ident{Kind: Const, Decl: true}},
+		{"iota", 1, Ident{Kind: Const, Builtin: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			offset := index(string(input), test.code, test.n)
+			if offset < 0 {
+				t.Fatalf("%q not found", test.code)
+			}
+			id, ok := prog.Lookup(path, offset)
+			if !ok {
+				t.Fatalf("identifier at offset %d not classified", offset)
+			}
+			if id != test.id {
+				t.Errorf("got %+v, want %+v", id, test.id)
+			}
+		})
+	}
+}
+
+// index returns the offset of the n-th occurrence of substr in s, or -1.
+func index(s, substr string, n int) int {
+	offset := 0
+	for ; n > 0; n-- {
+		i := strings.Index(s[offset:], substr)
+		if i < 0 {
+			return -1
+		}
+		offset += i
+		if n > 1 {
+			offset += len(substr)
+		}
+	}
+
+	return offset
+}
+
+// TestDecl tests that uses of package level entities, methods and fields are
+// resolved to their declaration, and that local entities are not.
+func TestDecl(t *testing.T) {
+	path, err := filepath.Abs("testdata/shadow.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &Source{
+		ImportPath: "shadow",
+		Name:       "shadow",
+		Files:      []string{path},
+	}
+	prog := Load([]*Source{src}, nil)
+
+	var tests = []struct {
+		use  string // code to search, identifier is at the start
+		decl string // code of the declaration, or "" if not linked
+	}{
+		{"T)", "T struct"},
+		{"T struct", "T struct"},
+		{"Name,", "Name string"},
+		{"Len()", "Len()"},
+		{"len\n", ""},
+		{"Loop\n", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.use, func(t *testing.T) {
+			offset := index(string(input), test.use, 1)
+			pos, ok := prog.Decl(path, offset)
+			if test.decl == "" {
+				if ok {
+					t.Errorf("expected no declaration, got %v", pos)
+				}
+
+				return
+			}
+			if !ok {
+				t.Fatal("expected declaration")
+			}
+			want := index(string(input), test.decl, 1)
+			if pos.Filename != path || pos.Offset != want {
+				t.Errorf("got offset %d, want %d", pos.Offset, want)
+			}
+		})
+	}
+}
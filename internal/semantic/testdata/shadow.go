@@ -0,0 +1,24 @@
+package shadow
+
+import "strings"
+
+type T struct {
+	Name string
+}
+
+func (t *T) Len() int {
+	len := strings.Count(t.Name, "")
+
+	return len
+}
+
+func f(len int) int {
+Loop:
+	for {
+		break Loop
+	}
+
+	return len
+}
+
+const C = iota
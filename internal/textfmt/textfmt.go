@@ -0,0 +1,344 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package textfmt implements a simple formatter for the files of a Go package
+// that are not Go source files: Go assembly, C, go.mod and plain text files.
+// The output uses the same representation as goefmt, consisting of lines and
+// spans, so that the files can be printed in the same way.
+package textfmt
+
+import (
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/perillo/goprint/internal/goefmt"
+)
+
+// Language describes the lexical syntax of a language, as required for
+// highlighting.
+type Language struct {
+	Name         string
+	LineComments []string        // line comment prefixes
+	BlockComment [2]string       // block comment delimiters, if any
+	Quotes       string          // string and character literal delimiters
+	Keywords     map[string]bool // keywords and pseudo instructions
+	Directives   bool            // '#' starts a preprocessor directive
+	Dollar       bool            // '$' starts an immediate operand
+	WordChars    string          // punctuation allowed in words
+}
+
+// Supported languages.
+var (
+	Asm = &Language{
+		Name:         "asm",
+		LineComments: []string{"//"},
+		BlockComment: [2]string{"/*", "*/"},
+		Quotes:       `"'`,
+		Keywords: set(
+			"TEXT", "DATA", "GLOBL", "FUNCDATA", "PCDATA", "BYTE", "WORD",
+			"NOSPLIT", "NOFRAME", "RODATA", "NOPTR", "DUPOK", "WRAPPER",
+			"NEEDCTXT", "TLSBSS", "TOPFRAME", "ABIInternal",
+		),
+		Directives: true,
+		Dollar:     true,
+	}
+
+	C = &Language{
+		Name:         "c",
+		LineComments: []string{"//"},
+		BlockComment: [2]string{"/*", "*/"},
+		Quotes:       `"'`,
+		Keywords: set(
+			"auto", "break", "case", "char", "const", "continue", "default",
+			"do", "double", "else", "enum", "extern", "float", "for", "goto",
+			"if", "inline", "int", "long", "register", "restrict", "return",
+			"short", "signed", "sizeof", "static", "struct", "switch",
+			"typedef", "union", "unsigned", "void", "volatile", "while",
+			"_Bool", "_Complex",
+		),
+		Directives: true,
+	}
+
+	GoMod = &Language{
+		Name:         "go.mod",
+		LineComments: []string{"//"},
+		Quotes:       "\"`",
+		Keywords: set(
+			"module", "go", "toolchain", "godebug", "require", "replace",
+			"exclude", "retract", "tool", "ignore",
+		),
+		WordChars: "/-+~@",
+	}
+)
+
+// set returns a set with the specified words.
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+
+	return m
+}
+
+// Detect returns the language of the file with the specified name, or nil if
+// the file is plain text.
+func Detect(name string) *Language {
+	switch base := filepath.Base(name); {
+	case base == "go.mod":
+		return GoMod
+	case strings.HasSuffix(base, ".s"), strings.HasSuffix(base, ".S"):
+		return Asm
+	case strings.HasSuffix(base, ".c"), strings.HasSuffix(base, ".h"):
+		return C
+	}
+
+	return nil
+}
+
+// Format splits the input in lines of spans, highlighted according to the
+// language lang.  If lang is nil, the input is plain text and each word is a
+// span.  As with goefmt.Format, empty lines are nil, the last line is the text
+// after the last newline, and carriage return characters are discarded.
+//
+// Code spans of the same kind as Go tokens have the token of the same kind;
+// keywords and other text have an explicit class.
+func Format(lang *Language, input []byte) chan goefmt.Line {
+	lines := make(chan goefmt.Line)
+	go func() {
+		s := &scanner{lang: lang, src: string(input)}
+		s.scan()
+		for _, line := range s.lines {
+			lines <- line
+		}
+		close(lines)
+	}()
+
+	return lines
+}
+
+// Classes of the spans that are not highlighted according to their token.
+var (
+	keywordClass = []string{"keyword"}
+	textClass    = []string{"text"}
+)
+
+type scanner struct {
+	lang  *Language
+	src   string
+	off   int // current offset
+	lines []goefmt.Line
+	cur   goefmt.Line
+}
+
+// scan splits the source in lines of spans.
+func (s *scanner) scan() {
+	bol := true // at the begin of a line, ignoring white space
+	for s.off < len(s.src) {
+		c := s.src[s.off]
+		switch {
+		case c == '\n':
+			s.newline()
+			s.off++
+			bol = true
+
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			s.space()
+
+			continue
+		}
+
+		lang := s.lang
+		start := s.off
+		switch {
+		case lang == nil:
+			s.word(start, token.IDENT, textClass, isText)
+		case s.lineComment():
+			s.emit(token.COMMENT, nil, start, strings.IndexByte(s.src[start:], '\n'))
+		case lang.BlockComment[0] != "" && strings.HasPrefix(s.src[s.off:], lang.BlockComment[0]):
+			s.blockComment()
+		case strings.IndexByte(lang.Quotes, c) >= 0:
+			s.quoted(c)
+		case lang.Directives && bol && c == '#':
+			s.off++
+			s.word(start, token.IDENT, keywordClass, isWord)
+		case lang.Dollar && c == '$' || isDigit(c):
+			s.off++
+			s.word(start, token.INT, nil, isWord)
+		case isWord(rune(c)) || c >= utf8.RuneSelf:
+			s.word(start, token.IDENT, textClass, func(r rune) bool {
+				return isWord(r) || strings.ContainsRune(lang.WordChars, r)
+			})
+			if lang.Keywords[s.src[start:s.off]] {
+				s.cur[len(s.cur)-1].Class = keywordClass
+			}
+		default:
+			// Punctuation, with runs of operator characters as a single span.
+			s.off++
+			for strings.IndexByte(operators, c) >= 0 && s.off < len(s.src) &&
+				strings.IndexByte(operators, s.src[s.off]) >= 0 && !s.lineComment() {
+				s.off++
+			}
+			s.emit(token.IDENT, textClass, start, s.off-start)
+		}
+		bol = false
+	}
+	s.newline()
+}
+
+// newline terminates the current line.
+func (s *scanner) newline() {
+	s.lines = append(s.lines, s.cur)
+	s.cur = nil
+}
+
+// space adds the white space at the current offset to the last span, or to a
+// new span with only white space at the begin of a line.  Carriage return and
+// other vertical white space characters are discarded.
+func (s *scanner) space() {
+	start := s.off
+	for s.off < len(s.src) && strings.IndexByte(" \t\r\f\v", s.src[s.off]) >= 0 {
+		s.off++
+	}
+	ws := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' {
+			return r
+		}
+
+		return -1
+	}, s.src[start:s.off])
+	if ws == "" {
+		return
+	}
+	if n := len(s.cur); n > 0 {
+		s.cur[n-1].Whitespace += ws
+	} else {
+		s.cur = append(s.cur, &goefmt.Span{Whitespace: ws, Offset: -1})
+	}
+}
+
+// emit adds a span with n bytes starting at offset start, or all the bytes
+// until the end of the source if n < 0.  The current offset is moved to the
+// end of the span.
+func (s *scanner) emit(tok token.Token, class []string, start, n int) {
+	if n < 0 {
+		n = len(s.src) - start
+	}
+	code := strings.TrimRight(s.src[start:start+n], "\r")
+	s.cur = append(s.cur, &goefmt.Span{
+		Token:  tok,
+		Code:   code,
+		Offset: start,
+		Class:  class,
+	})
+	s.off = start + n
+}
+
+// word adds a span starting at offset start, with the characters following
+// the current offset for which valid returns true.
+func (s *scanner) word(start int, tok token.Token, class []string, valid func(rune) bool) {
+	for s.off < len(s.src) {
+		r, size := utf8.DecodeRuneInString(s.src[s.off:])
+		if !valid(r) {
+			break
+		}
+		s.off += size
+	}
+	if s.off == start {
+		// Always make progress.
+		_, size := utf8.DecodeRuneInString(s.src[s.off:])
+		s.off += size
+	}
+	s.emit(tok, class, start, s.off-start)
+}
+
+// lineComment returns true if a line comment starts at the current offset.
+func (s *scanner) lineComment() bool {
+	for _, prefix := range s.lang.LineComments {
+		if strings.HasPrefix(s.src[s.off:], prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// blockComment adds the spans of the block comment at the current offset, one
+// for each line.
+func (s *scanner) blockComment() {
+	start := s.off
+	end := strings.Index(s.src[start+len(s.lang.BlockComment[0]):], s.lang.BlockComment[1])
+	if end < 0 {
+		end = len(s.src)
+	} else {
+		end += start + len(s.lang.BlockComment[0]) + len(s.lang.BlockComment[1])
+	}
+	for {
+		i := strings.IndexByte(s.src[s.off:end], '\n')
+		if i < 0 {
+			s.emit(token.COMMENT, nil, s.off, end-s.off)
+
+			return
+		}
+		if i > 0 {
+			s.emit(token.COMMENT, nil, s.off, i)
+		}
+		s.newline()
+		s.off++
+
+		// Keep the indentation as white space.
+		for s.off < end && (s.src[s.off] == ' ' || s.src[s.off] == '\t') {
+			s.space()
+		}
+	}
+}
+
+// quoted adds a span with the string or character literal at the current
+// offset, delimited by q.  The literal ends at the end of the line, if not
+// terminated.
+func (s *scanner) quoted(q byte) {
+	start := s.off
+	i := start + 1
+	for i < len(s.src) && s.src[i] != '\n' {
+		c := s.src[i]
+		i++
+		if c == '\\' && q != '`' && i < len(s.src) && s.src[i] != '\n' {
+			i++
+
+			continue
+		}
+		if c == q {
+			break
+		}
+	}
+	tok := token.STRING
+	if q == '\'' {
+		tok = token.CHAR
+	}
+	s.emit(tok, nil, start, i-start)
+}
+
+// operators are the characters of the operators spanning more characters.
+const operators = "!%&*+-/<=>?^|~:"
+
+// isWord returns true if r can be part of an identifier or a number.  The
+// middle dot and the slash are used in Go assembly symbols.
+func isWord(r rune) bool {
+	return r == '_' || r == '.' || r == '·' || r == '∕' ||
+		unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isText returns true if r can be part of a word of plain text.
+func isText(r rune) bool {
+	return !unicode.IsSpace(r)
+}
+
+// isDigit returns true if c is a decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package textfmt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perillo/goprint/internal/goefmt"
+)
+
+// format returns a compact representation of the lines: each span is
+// represented by its first class and code, followed by its white space.
+func format(lines chan goefmt.Line) []string {
+	var buf []string
+	for line := range lines {
+		var spans []string
+		for _, s := range line {
+			if s.Code == "" {
+				spans = append(spans, s.Whitespace)
+
+				continue
+			}
+			class := goefmt.TokenClass(s)[0]
+			spans = append(spans, class+":"+s.Code+s.Whitespace)
+		}
+		buf = append(buf, strings.Join(spans, "|"))
+	}
+
+	return buf
+}
+
+var formatTests = []struct {
+	lang *Language
+	src  string
+	want []string
+}{
+	{
+		GoMod,
+		"module example.com/m // comment\n\nrequire (\n\tgolang.org/x/text v0.3.0\r\n)\n",
+		[]string{
+			"keyword:module |text:example.com/m |comment:// comment",
+			"",
+			"keyword:require |text:(",
+			"\t|text:golang.org/x/text |text:v0.3.0",
+			"text:)",
+			"",
+		},
+	},
+	{
+		Asm,
+		"#include \"textflag.h\"\n\nTEXT ·add(SB), NOSPLIT, $0-24\n\tMOVQ x+0(FP), AX /* a\n\t b */\n\tRET",
+		[]string{
+			"keyword:#include |literal:\"textflag.h\"",
+			"",
+			"keyword:TEXT |text:·add|text:(|text:SB|text:)|text:, |keyword:NOSPLIT|text:, |literal:$0|text:-|literal:24",
+			"\t|text:MOVQ |text:x|text:+|literal:0|text:(|text:FP|text:)|text:, |text:AX |comment:/* a",
+			"\t |comment:b */",
+			"\t|text:RET",
+		},
+	},
+	{
+		C,
+		"static int f(char c) { return c == '\\''; }\n",
+		[]string{
+			"keyword:static |keyword:int |text:f|text:(|keyword:char |text:c|text:) |text:{ |keyword:return |text:c |text:== |literal:'\\''|text:; |text:}",
+			"",
+		},
+	},
+	{
+		nil,
+		"A <b>README</b>.\n  Indented text.",
+		[]string{
+			"text:A |text:<b>README</b>.",
+			"  |text:Indented |text:text.",
+		},
+	},
+}
+
+func TestFormat(t *testing.T) {
+	for _, test := range formatTests {
+		got := format(Format(test.lang, []byte(test.src)))
+		if len(got) != len(test.want) {
+			t.Errorf("%q: got %d lines, want %d: %q", test.src, len(got), len(test.want), got)
+
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%q: line %d: got %q, want %q", test.src, i+1, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	var tests = []struct {
+		name string
+		want *Language
+	}{
+		{"go.mod", GoMod},
+		{"asm_amd64.s", Asm},
+		{"gcc_amd64.S", Asm},
+		{"hello.c", C},
+		{"hello.h", C},
+		{"README.md", nil},
+		{"testdata/data.txt", nil},
+	}
+
+	for _, test := range tests {
+		if got := Detect(test.name); got != test.want {
+			t.Errorf("Detect(%q): got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
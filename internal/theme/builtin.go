@@ -0,0 +1,88 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package theme
+
+import (
+	"sort"
+	"strings"
+)
+
+// builtins maps the name of each builtin theme to its definition.
+var builtins = map[string]string{
+	// For laser printers, using only bold and italics.
+	"monochrome": `
+line       color=#999
+keyword    bold
+builtin    bold italic
+ident.decl bold
+ident.type underline
+literal    italic
+comment    italic
+invalid    background=#f00
+`,
+
+	"color": `
+line       color=#999
+keyword    bold color=#00007f
+builtin    bold color=#7f007f
+ident.decl bold
+ident.type underline color=#005f5f
+literal    color=#a31515
+comment    italic color=#007f00
+invalid    background=#f00
+`,
+
+	// Black text on white, with the token kinds distinguished by style and
+	// dark colors only.
+	"high-contrast": `
+line       color=#000
+keyword    bold color=#000
+builtin    bold italic color=#000
+ident.decl bold underline
+ident.type underline
+literal    color=#00005f
+comment    italic color=#3f3f3f
+invalid    color=#fff background=#000
+`,
+
+	// The Okabe-Ito palette, distinguishable with all common forms of color
+	// blindness.
+	"colorblind": `
+line       color=#999
+keyword    bold color=#0072b2
+builtin    bold color=#cc79a7
+ident.decl bold
+ident.type underline color=#009e73
+literal    color=#d55e00
+comment    italic color=#666
+invalid    background=#e69f00
+`,
+}
+
+// Builtin returns the builtin theme with the specified name.
+func Builtin(name string) (*Theme, bool) {
+	def, ok := builtins[name]
+	if !ok {
+		return nil, false
+	}
+	t, err := Parse(strings.NewReader(def))
+	if err != nil {
+		panic("invalid builtin theme " + name + ": " + err.Error())
+	}
+	t.Name = name
+
+	return t, true
+}
+
+// Names returns the names of the builtin themes, sorted.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package theme implements the color and typography themes used to highlight
+// the source code.
+//
+// A theme is a list of rules, one for each line of a theme file.  A rule has a
+// selector, with one or more HTML classes separated by '.', as produced by
+// goefmt.TokenClass and by the type checker, followed by the properties to
+// apply to the code spans having all the classes:
+//
+//	bold
+//	italic
+//	underline
+//	color=#rrggbb
+//	background=#rrggbb
+//
+// The "line" class selects the line numbers.  Empty lines and lines starting
+// with '#' are ignored.  As in CSS, when more rules apply to a code span,
+// rules with more classes take precedence; otherwise the last rule takes
+// precedence.  Bold, italic and underline properties can not be unset.
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Color represents an RGB color.
+type Color struct {
+	R, G, B uint8
+}
+
+// String implements the Stringer interface.
+func (c Color) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// parseColor parses a color in the #rrggbb or #rgb notation.
+func parseColor(s string) (Color, error) {
+	if !strings.HasPrefix(s, "#") || (len(s) != 4 && len(s) != 7) {
+		return Color{}, fmt.Errorf("invalid color: %q", s)
+	}
+	hex := s[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color: %q", s)
+	}
+
+	return Color{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// Style represents the style of a code span.  Nil colors are inherited.
+type Style struct {
+	Color      *Color
+	Background *Color
+	Bold       bool
+	Italic     bool
+	Underline  bool
+}
+
+// merge applies the style s2 to s.
+func (s *Style) merge(s2 Style) {
+	if s2.Color != nil {
+		s.Color = s2.Color
+	}
+	if s2.Background != nil {
+		s.Background = s2.Background
+	}
+	s.Bold = s.Bold || s2.Bold
+	s.Italic = s.Italic || s2.Italic
+	s.Underline = s.Underline || s2.Underline
+}
+
+// Rule represents a theme rule.
+type Rule struct {
+	Class []string // classes a code span must have
+	Style
+}
+
+// matches returns true if the code span with the specified classes has all
+// the classes of rule r.
+func (r *Rule) matches(class []string) bool {
+	for _, c := range r.Class {
+		found := false
+		for _, c2 := range class {
+			if c == c2 {
+				found = true
+
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Theme represents a color and typography theme.
+type Theme struct {
+	Name  string
+	Rules []Rule
+}
+
+// String implements the Stringer interface.
+func (t Theme) String() string {
+	return t.Name
+}
+
+// Set implements the Value interface.  s is the name of a builtin theme or
+// the path of a theme file.
+func (t *Theme) Set(s string) error {
+	if v, ok := Builtin(s); ok {
+		*t = *v
+
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(s)
+	if err != nil {
+		return fmt.Errorf("load theme: %v", err)
+	}
+	v, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("load theme %s: %v", s, err)
+	}
+	v.Name = s
+	*t = *v
+
+	return nil
+}
+
+// Style returns the style of a code span with the specified classes.
+func (t *Theme) Style(class []string) Style {
+	var style Style
+	for _, r := range t.ordered() {
+		if r.matches(class) {
+			style.merge(r.Style)
+		}
+	}
+
+	return style
+}
+
+// ordered returns the rules sorted by precedence, as in the CSS cascade.
+func (t *Theme) ordered() []Rule {
+	rules := append([]Rule(nil), t.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].Class) < len(rules[j].Class)
+	})
+
+	return rules
+}
+
+// CSS returns the theme as CSS rules.
+func (t *Theme) CSS() string {
+	var buf strings.Builder
+	for _, r := range t.Rules {
+		fmt.Fprintf(&buf, ".%s {\n", strings.Join(r.Class, "."))
+		if r.Bold {
+			buf.WriteString("\tfont-weight: bold;\n")
+		}
+		if r.Italic {
+			buf.WriteString("\tfont-style: italic;\n")
+		}
+		if r.Underline {
+			buf.WriteString("\ttext-decoration: underline;\n")
+		}
+		if r.Color != nil {
+			fmt.Fprintf(&buf, "\tcolor: %v;\n", r.Color)
+		}
+		if r.Background != nil {
+			fmt.Fprintf(&buf, "\tbackground-color: %v;\n", r.Background)
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	return buf.String()
+}
+
+// Parse parses a theme file.
+func Parse(r io.Reader) (*Theme, error) {
+	t := new(Theme)
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		rule, err := parseRule(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		t.Rules = append(t.Rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// parseRule parses a rule, split in fields.
+func parseRule(fields []string) (Rule, error) {
+	var r Rule
+	for _, c := range strings.Split(strings.TrimPrefix(fields[0], "."), ".") {
+		if c == "" {
+			return r, fmt.Errorf("invalid selector: %q", fields[0])
+		}
+		r.Class = append(r.Class, c)
+	}
+
+	for _, prop := range fields[1:] {
+		name, value := prop, ""
+		if i := strings.IndexByte(prop, '='); i >= 0 {
+			name, value = prop[:i], prop[i+1:]
+		}
+		switch name {
+		case "bold":
+			r.Bold = true
+		case "italic":
+			r.Italic = true
+		case "underline":
+			r.Underline = true
+		case "color", "background":
+			c, err := parseColor(value)
+			if err != nil {
+				return r, err
+			}
+			if name == "color" {
+				r.Color = &c
+			} else {
+				r.Background = &c
+			}
+		default:
+			return r, fmt.Errorf("invalid property: %q", prop)
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package theme
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const src = `
+# A test theme.
+keyword    bold color=#00f
+ident.decl bold underline
+ident      color=#333333
+.comment   italic background=#eeeeee
+`
+
+func TestParse(t *testing.T) {
+	theme, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(theme.Rules) != 4 {
+		t.Fatalf("got %d rules, want 4", len(theme.Rules))
+	}
+
+	var tests = []struct {
+		class []string
+		want  string // color, background, bold, italic, underline
+	}{
+		{[]string{"keyword"}, "#0000ff <nil> true false false"},
+		{[]string{"ident"}, "#333333 <nil> false false false"},
+		{[]string{"ident", "decl"}, "#333333 <nil> true false true"},
+		{[]string{"comment"}, "<nil> #eeeeee false true false"},
+		{[]string{"operator"}, "<nil> <nil> false false false"},
+	}
+	for _, test := range tests {
+		s := theme.Style(test.class)
+		got := fmt.Sprintf("%s %s %t %t %t", str(s.Color), str(s.Background),
+			s.Bold, s.Italic, s.Underline)
+		if got != test.want {
+			t.Errorf("Style(%q): got %s, want %s", test.class, got, test.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	var tests = []string{
+		"keyword bolder\n",
+		"keyword color=blue\n",
+		"keyword color=#12345\n",
+		"keyword color=#ggg\n",
+		"ident..decl bold\n",
+	}
+
+	for _, test := range tests {
+		if _, err := Parse(strings.NewReader(test)); err == nil {
+			t.Errorf("Parse(%q): expected error", test)
+		}
+	}
+}
+
+func TestCSS(t *testing.T) {
+	theme, err := Parse(strings.NewReader("ident.decl bold color=#102030\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	const want = ".ident.decl {\n\tfont-weight: bold;\n\tcolor: #102030;\n}\n\n"
+	if got := theme.CSS(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	for _, name := range Names() {
+		theme, ok := Builtin(name)
+		if !ok {
+			t.Errorf("Builtin(%q): not found", name)
+
+			continue
+		}
+		if theme.Name != name {
+			t.Errorf("Builtin(%q): got name %q", name, theme.Name)
+		}
+	}
+	if _, ok := Builtin("xxx"); ok {
+		t.Errorf("Builtin(%q): expected not found", "xxx")
+	}
+}
+
+func str(c *Color) string {
+	if c == nil {
+		return "<nil>"
+	}
+
+	return c.String()
+}
@@ -0,0 +1,495 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/perillo/goprint/internal/cover"
+	"github.com/perillo/goprint/internal/css"
+	"github.com/perillo/goprint/internal/goefmt"
+	"github.com/perillo/goprint/internal/packages"
+	"github.com/perillo/goprint/internal/theme"
+)
+
+// texLayout writes Go source files in a LaTeX document, with the same layout
+// used for printing the HTML document.
+type texLayout struct {
+	w        *bufio.Writer
+	r        *renderer
+	mod      *packages.Module
+	wrap     int  // maximum number of characters in a code line
+	fontspec bool // the font is loaded with fontspec, by XeLaTeX or LuaLaTeX
+}
+
+// writeLaTeX writes on w a LaTeX document with the source files of all the
+// packages in pkglist, belonging to module mod.  The source files are
+// formatted using r, and highlighted according to the theme.
+//
+// When fragment is true, only the document body is written, to be included
+// in another document; otherwise the document is standalone.  Both use the
+// fancyhdr package for the page margin boxes.
+func writeLaTeX(w io.Writer, mod *packages.Module, pkglist []Package, r *renderer, fragment bool) error {
+	l := &texLayout{
+		w:    bufio.NewWriter(w),
+		r:    r,
+		mod:  mod,
+		wrap: wrapWidth(0.6 * font.Size.Points()), // Courier character width
+
+		fontspec: !fragment && (font.Family != "Courier" || len(fontFiles) > 0),
+	}
+
+	boxes, err := texBoxes(mod, sourceDir(mod, pkglist))
+	if err != nil {
+		return err
+	}
+	if fragment {
+		fmt.Fprintf(l.w, "%% Generated by goprint.  It requires the fancyhdr, lastpage,\n")
+		fmt.Fprintf(l.w, "%% multicol, textcomp and xcolor packages.\n")
+	} else {
+		l.preamble()
+		fmt.Fprintf(l.w, "\\begin{document}\n")
+	}
+	l.setup(boxes)
+	if *coverPage {
+		l.cover(newCover(mod, pkglist))
+	}
+	for _, pkg := range pkglist {
+		for _, file := range pkg.Files {
+			l.layout(pkg, file)
+		}
+	}
+	if !fragment {
+		fmt.Fprintf(l.w, "\\end{document}\n")
+	}
+	if err := l.w.Flush(); err != nil {
+		return fmt.Errorf("write LaTeX: %v", err)
+	}
+
+	return nil
+}
+
+// preamble writes the preamble of a standalone document, with the page
+// layout and the font specified on the command line.  Fonts other than
+// Courier, and the font files, require XeLaTeX or LuaLaTeX.
+func (l *texLayout) preamble() {
+	width, height := pageSize.Size()
+	em := font.Size.Points()
+
+	fmt.Fprintf(l.w, "\\documentclass{article}\n\n")
+	if l.fontspec {
+		fmt.Fprintf(l.w, "\\usepackage{fontspec}\n")
+		if len(fontFiles) > 0 {
+			fmt.Fprintf(l.w, "\\setmonofont%s\n", texFontFiles(fontFiles))
+		} else {
+			fmt.Fprintf(l.w, "\\setmonofont{%s}\n", texEscape(font.Family))
+		}
+	} else {
+		fmt.Fprintf(l.w, "\\usepackage[T1]{fontenc}\n")
+		fmt.Fprintf(l.w, "\\usepackage[utf8]{inputenc}\n")
+		fmt.Fprintf(l.w, "\\usepackage{textcomp}\n")
+		fmt.Fprintf(l.w, "\\usepackage{courier}\n")
+	}
+	fmt.Fprintf(l.w, "\\usepackage[paperwidth=%s,paperheight=%s,top=%s,right=%s,"+
+		"bottom=%s,left=%s,headsep=%s,footskip=%s]{geometry}\n",
+		texDimension(width), texDimension(height),
+		texDimension(pageMargin.Top), texDimension(pageMargin.Right),
+		texDimension(pageMargin.Bottom), texDimension(pageMargin.Left),
+		texPoints(1.5*em), texPoints(2.5*em))
+	fmt.Fprintf(l.w, "\\usepackage{fancyhdr}\n")
+	fmt.Fprintf(l.w, "\\usepackage{lastpage}\n")
+	fmt.Fprintf(l.w, "\\usepackage{multicol}\n")
+	fmt.Fprintf(l.w, "\\usepackage{xcolor}\n\n")
+	fmt.Fprintf(l.w, "\\renewcommand{\\familydefault}{\\ttdefault}\n\n")
+}
+
+// setup writes the definitions of the commands used in the document body, and
+// the page style with the page margin boxes.  The definitions are skipped when
+// already defined, so that the document can be included more than once, and
+// the commands can be redefined.
+func (l *texLayout) setup(boxes [numBoxes]string) {
+	em := font.Size.Points()
+
+	fmt.Fprintf(l.w, "\\providecommand{\\goprintpackage}{}\n")
+	fmt.Fprintf(l.w, "\\providecommand{\\goprintfilename}{}\n")
+	fmt.Fprintf(l.w, "\\providecommand{\\goprintmodule}{}\n")
+	fmt.Fprintf(l.w, "\\providecommand{\\goprintcoverage}{}\n")
+	fmt.Fprintf(l.w, "%% \\goprintfile{package}{file}{module}{coverage} starts a new file.\n")
+	fmt.Fprintf(l.w, "\\providecommand{\\goprintfile}[4]{\\clearpage"+
+		"\\gdef\\goprintpackage{#1}\\gdef\\goprintfilename{#2}"+
+		"\\gdef\\goprintmodule{#3}\\gdef\\goprintcoverage{#4}}\n")
+	fmt.Fprintf(l.w, "%% \\goprintchar{code} prints the character with the hexadecimal code point,\n")
+	if l.fontspec {
+		fmt.Fprintf(l.w, "\\providecommand{\\goprintchar}[1]{\\symbol{\"#1}}\n")
+	} else {
+		fmt.Fprintf(l.w, "%% not available with inputenc; with XeLaTeX or LuaLaTeX, redefine it as\n")
+		fmt.Fprintf(l.w, "%% \\symbol{\"#1}.\n")
+		fmt.Fprintf(l.w, "\\providecommand{\\goprintchar}[1]{\\textbf{?}}\n")
+	}
+	fmt.Fprintf(l.w, "%% The goprintcode environment.\n")
+	fmt.Fprintf(l.w, "\\providecommand{\\goprintcode}{\\par\\ttfamily"+
+		"\\fontsize{%s}{%s}\\selectfont\\setlength{\\parindent}{0pt}"+
+		"\\setlength{\\parskip}{0pt}\\setlength{\\fboxsep}{0pt}}\n",
+		texDimension(font.Size), texDimension(font.LineHeight))
+	fmt.Fprintf(l.w, "\\providecommand{\\endgoprintcode}{\\par}\n\n")
+
+	fmt.Fprintf(l.w, "\\makeatletter\n")
+	fmt.Fprintf(l.w, "\\@ifundefined{ps@goprint}{\\fancypagestyle{goprint}{%%\n")
+	fmt.Fprintf(l.w, "\t\\fancyhf{}%%\n")
+	for i, box := range boxes {
+		if box == "" {
+			continue
+		}
+
+		cmd, pos := "fancyhead", "LCR"[i%3]
+		if i >= bottomLeft {
+			cmd = "fancyfoot"
+		}
+		fmt.Fprintf(l.w, "\t\\%s[%c]{\\ttfamily\\fontsize{%s}{%s}\\selectfont %s}%%\n",
+			cmd, pos, texPoints(em), texPoints(em), box)
+	}
+	fmt.Fprintf(l.w, "\t\\renewcommand{\\headrulewidth}{0pt}%%\n")
+	fmt.Fprintf(l.w, "}}{}\n")
+	fmt.Fprintf(l.w, "\\makeatother\n")
+	fmt.Fprintf(l.w, "\\pagestyle{goprint}\n\n")
+}
+
+// cover writes the cover page c, without page margin boxes and not numbered.
+func (l *texLayout) cover(c *Cover) {
+	fmt.Fprintf(l.w, "\\begin{titlepage}\n")
+	fmt.Fprintf(l.w, "\\centering\n")
+	fmt.Fprintf(l.w, "\\vspace*{0.3\\textwidth}\n")
+	if c.Title != "" {
+		fmt.Fprintf(l.w, "{\\Large\\bfseries %s\\par}\n", texEscape(c.Title))
+	}
+	if c.Author != "" {
+		fmt.Fprintf(l.w, "\\vspace{2em}\n%s\\par\n", texEscape(c.Author))
+	}
+	fmt.Fprintf(l.w, "\\vspace{2em}\n")
+	fmt.Fprintf(l.w, "\\begin{tabular}{ll}\n")
+	for _, f := range c.Fields() {
+		fmt.Fprintf(l.w, "\\textbf{%s} & %s \\\\\n", texEscape(f[0]), texEscape(f[1]))
+	}
+	fmt.Fprintf(l.w, "\\end{tabular}\n")
+	fmt.Fprintf(l.w, "\\end{titlepage}\n\n")
+}
+
+// layout writes the source file of package pkg, starting from a new page.
+func (l *texLayout) layout(pkg Package, file File) {
+	coverage := coverageLabel(file.Coverage, pkg.Coverage)
+	mod := l.mod
+	if mod == nil {
+		// The packages belong to different modules.
+		mod = pkg.Module
+	}
+	fmt.Fprintf(l.w, "\\goprintfile{%s}{%s}{%s}{%s}\n", texEscape(pkg.ImportPath),
+		texEscape(file.Name), texEscape(mod.String()), texEscape(coverage))
+	fmt.Fprintf(l.w, "\\begin{goprintcode}\n")
+	if *columns > 1 {
+		fmt.Fprintf(l.w, "\\setlength{\\columnsep}{2em}\n")
+		fmt.Fprintf(l.w, "\\setlength{\\columnseprule}{0.5pt}\n")
+		fmt.Fprintf(l.w, "\\begin{multicols*}{%d}\n", *columns)
+	}
+
+	n := 1
+	last := 0 // last line included
+	status := l.r.lineStatus(file.Path, file.input)
+	for line := range formatFile(file.Path, file.input) {
+		if !inRanges(file.ranges, n) {
+			n++
+
+			continue
+		}
+		if n > last+1 {
+			l.elision()
+		}
+		last = n
+		shade := cover.NotInstrumented
+		if n <= len(status) {
+			shade = status[n-1]
+		}
+		for i, row := range goefmt.Wrap(line, l.wrap, tabsize) {
+			l.line(file.Path, n, i > 0, row, shade)
+		}
+		n++
+	}
+	if last < lineCount(file.input) {
+		l.elision()
+	}
+
+	if *columns > 1 {
+		fmt.Fprintf(l.w, "\\end{multicols*}\n")
+	}
+	fmt.Fprintf(l.w, "\\end{goprintcode}\n\n")
+}
+
+// elision writes the marker for a run of skipped lines.
+func (l *texLayout) elision() {
+	number := texStyle(texCode(fmt.Sprintf("%3s", elision)), codeTheme.Style([]string{"line"}))
+	fmt.Fprintf(l.w, "\\mbox{%s}\\par\n", number)
+}
+
+// line writes the code line number n of the source file named by path.  When
+// cont is true, line is the continuation of a wrapped line.  The line is
+// shaded according to its coverage status.
+func (l *texLayout) line(path string, n int, cont bool, line goefmt.Line, shade cover.Status) {
+	var buf strings.Builder
+	lineStyle := codeTheme.Style([]string{"line"})
+	if cont {
+		buf.WriteString(texStyle(texCode(fmt.Sprintf("%3s", continuation)), lineStyle))
+	} else {
+		buf.WriteString(texStyle(texCode(fmt.Sprintf("%3d", n)), lineStyle))
+	}
+	if s, _, ok := l.r.blameAnnotation(path, n, cont); ok {
+		buf.WriteString("~" + texStyle(texCode(s), lineStyle))
+	}
+	buf.WriteString("~")
+
+	col := 0 // for tab expansion
+	for _, s := range line {
+		if s.Code != "" {
+			code := expandTabs(s.Code, &col)
+			buf.WriteString(texStyle(texCode(code), codeTheme.Style(l.r.spanClass(path, s))))
+		}
+		buf.WriteString(texCode(expandTabs(s.Whitespace, &col)))
+	}
+
+	code := "\\mbox{" + buf.String() + "}"
+	switch shade {
+	case cover.Covered:
+		code = "\\colorbox[HTML]{DDFFDD}{\\makebox[\\linewidth][l]{" + code + "}}"
+	case cover.Uncovered:
+		code = "\\colorbox[HTML]{FFDDDD}{\\makebox[\\linewidth][l]{" + code + "}}"
+	}
+	fmt.Fprintf(l.w, "%s\\par\n", code)
+}
+
+// texFontFiles returns the arguments of the fontspec \setmonofont command
+// loading the font files in paths, with the variants in fontVariants order.
+// The files are relative to the directory of the regular font, and the
+// missing bold and italic variants are synthesized.
+func texFontFiles(paths []string) string {
+	abs := func(path string) string {
+		if p, err := filepath.Abs(path); err == nil {
+			path = p
+		}
+
+		return path
+	}
+	dir := filepath.Dir(abs(paths[0]))
+	rel := func(path string) string {
+		if p, err := filepath.Rel(dir, abs(path)); err == nil {
+			path = p
+		}
+
+		return filepath.ToSlash(path)
+	}
+
+	opts := []string{"Path=" + filepath.ToSlash(dir) + "/"}
+	names := [...]string{"", "BoldFont", "ItalicFont", "BoldItalicFont"}
+	var have [4]bool
+	for i, path := range paths {
+		if i > 0 && path != "" {
+			opts = append(opts, names[i]+"="+rel(path))
+			have[i] = true
+		}
+	}
+	if !have[1] {
+		opts = append(opts, "AutoFakeBold=1.5")
+	}
+	if !have[2] {
+		opts = append(opts, "AutoFakeSlant=0.2")
+	}
+
+	return fmt.Sprintf("{%s}[%s]", rel(paths[0]), strings.Join(opts, ","))
+}
+
+// expandTabs returns s with the tabs expanded to spaces, starting at column
+// col.  col is updated to the column after s.
+func expandTabs(s string, col *int) string {
+	if !strings.ContainsRune(s, '\t') {
+		*col += len([]rune(s))
+
+		return s
+	}
+
+	var buf strings.Builder
+	for _, r := range s {
+		if r == '\t' {
+			n := tabsize - *col%tabsize
+			buf.WriteString(strings.Repeat(" ", n))
+			*col += n
+
+			continue
+		}
+		buf.WriteRune(r)
+		*col++
+	}
+
+	return buf.String()
+}
+
+// texStyle returns the LaTeX text s with the style of a code span.
+func texStyle(s string, style theme.Style) string {
+	if style.Bold {
+		s = "\\textbf{" + s + "}"
+	}
+	if style.Italic {
+		s = "\\textsl{" + s + "}"
+	}
+	if style.Underline {
+		s = "\\underline{" + s + "}"
+	}
+	if style.Color != nil {
+		s = "\\textcolor[HTML]{" + texColor(*style.Color) + "}{" + s + "}"
+	}
+	if style.Background != nil {
+		s = "\\colorbox[HTML]{" + texColor(*style.Background) + "}{" + s + "}"
+	}
+
+	return s
+}
+
+// texColor returns the color c in the HTML model of the xcolor package.
+func texColor(c theme.Color) string {
+	return strings.ToUpper(strings.TrimPrefix(c.String(), "#"))
+}
+
+// texSymbols maps the LaTeX special characters, and the characters that are
+// not printed as is in some font encodings, to LaTeX commands.  The commands
+// for non ASCII characters are available with the T1 and TS1 encodings.
+var texSymbols = map[rune]string{
+	'\\':           `\textbackslash{}`,
+	'{':            `\{`,
+	'}':            `\}`,
+	'$':            `\$`,
+	'&':            `\&`,
+	'#':            `\#`,
+	'%':            `\%`,
+	'_':            `\_`,
+	'^':            `\textasciicircum{}`,
+	'~':            `\textasciitilde{}`,
+	'\'':           `\textquotesingle{}`,
+	'`':            `\textasciigrave{}`,
+	'"':            `\textquotedbl{}`,
+	'<':            `\textless{}`,
+	'>':            `\textgreater{}`,
+	'-':            `-{}`,
+	'\u00a0':       `~`,
+	'\u0152':       `\OE{}`,
+	'\u0153':       `\oe{}`,
+	'\u0160':       `\v{S}`,
+	'\u0161':       `\v{s}`,
+	'\u0178':       `\"{Y}`,
+	'\u017d':       `\v{Z}`,
+	'\u017e':       `\v{z}`,
+	'\u0192':       `\textflorin{}`,
+	'\u02c6':       `\textasciicircum{}`,
+	'\u02dc':       `\textasciitilde{}`,
+	'\u201a':       `\quotesinglbase{}`,
+	'\u201e':       `\quotedblbase{}`,
+	'\u2039':       `\guilsinglleft{}`,
+	'\u203a':       `\guilsinglright{}`,
+	'\u2003':       `\hspace{1em}`,
+	'\u2013':       `\textendash{}`,
+	'\u2014':       `\textemdash{}`,
+	'\u2018':       `\textquoteleft{}`,
+	'\u2019':       `\textquoteright{}`,
+	'\u201c':       `\textquotedblleft{}`,
+	'\u201d':       `\textquotedblright{}`,
+	'\u2020':       `\textdagger{}`,
+	'\u2021':       `\textdaggerdbl{}`,
+	'\u2022':       `\textbullet{}`,
+	'\u2030':       `\textperthousand{}`,
+	'\u2044':       `\textfractionsolidus{}`,
+	'\u20ac':       `\texteuro{}`,
+	'\u2122':       `\texttrademark{}`,
+	'\u2190':       `\textleftarrow{}`,
+	'\u2191':       `\textuparrow{}`,
+	'\u2192':       `\textrightarrow{}`,
+	'\u2193':       `\textdownarrow{}`,
+	'\u2212':       `\textminus{}`,
+	'\u2026':       `\dots{}`,           // elision
+	'\u00bb':       `\guillemotright{}`, // continuation
+	utf8.RuneError: `\goprintchar{FFFD}`,
+}
+
+// texEscape returns the text s escaped for LaTeX.  The printable ASCII and
+// Latin-1 characters are written as is, since they are declared by the
+// inputenc package; the other characters are written with the \goprintchar
+// command, with the hexadecimal code point as argument.
+func texEscape(s string) string {
+	var buf strings.Builder
+	for _, r := range s {
+		if sym, ok := texSymbols[r]; ok {
+			buf.WriteString(sym)
+
+			continue
+		}
+		if (r >= ' ' && r <= '~') || (r >= '\u00a0' && r <= '\u00ff') {
+			buf.WriteRune(r)
+
+			continue
+		}
+		fmt.Fprintf(&buf, `\goprintchar{%04X}`, r)
+	}
+
+	return buf.String()
+}
+
+// texCode returns the code s escaped for LaTeX.  Spaces are not breakable, and
+// they are never collapsed.
+func texCode(s string) string {
+	return strings.Replace(texEscape(s), " ", "~", -1)
+}
+
+// texDimension returns the dimension d in LaTeX units.
+func texDimension(d css.Dimension) string {
+	return texPoints(d.Points())
+}
+
+// texPoints returns v CSS points as a LaTeX dimension.  CSS points are LaTeX
+// big points.
+func texPoints(v float64) string {
+	return fmt.Sprintf("%.2fbp", v)
+}
+
+// texBoxes returns the page margin boxes for a document with module mod, as
+// LaTeX code.  The fields that change from file to file are commands defined
+// in the document.  dir is the directory of the source files.
+func texBoxes(mod *packages.Module, dir string) ([numBoxes]string, error) {
+	var content [numBoxes]string
+	boxes, err := parseBoxes()
+	if err != nil {
+		return content, err
+	}
+
+	data := boxData{
+		Package:  markup(`\goprintpackage{}`),
+		File:     markup(`\goprintfilename{}`),
+		Module:   markup(`\goprintmodule{}`),
+		Coverage: markup(`\goprintcoverage{}`),
+		Date:     mod.Date(),
+	}
+	for i, t := range boxes {
+		if t == nil {
+			continue
+		}
+
+		var buf strings.Builder
+		t.Funcs(boxFuncs(markup(`\thepage{}`), markup(`\pageref{LastPage}`), dir))
+		if err := t.Execute(&buf, data); err != nil {
+			return content, fmt.Errorf("execute: %v", err)
+		}
+		content[i] = expandBox(buf.String(), texEscape, "")
+	}
+
+	return content, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestTexFontFiles tests that texFontFiles loads the font files relative to
+// the directory of the regular font, synthesizing the missing variants.
+func TestTexFontFiles(t *testing.T) {
+	root, err := filepath.Abs("/fonts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.ToSlash(root) + "/"
+	path := func(name string) string {
+		return filepath.Join(root, filepath.FromSlash(name))
+	}
+
+	var tests = []struct {
+		paths []string
+		args  string
+	}{
+		{
+			[]string{path("Mono.ttf")},
+			"{Mono.ttf}[Path=" + dir + ",AutoFakeBold=1.5,AutoFakeSlant=0.2]",
+		},
+		{
+			[]string{path("Mono.ttf"), path("Mono-Bold.ttf")},
+			"{Mono.ttf}[Path=" + dir + ",BoldFont=Mono-Bold.ttf,AutoFakeSlant=0.2]",
+		},
+		{
+			[]string{path("Mono.ttf"), "", path("italic/Mono-Italic.ttf")},
+			"{Mono.ttf}[Path=" + dir + ",ItalicFont=italic/Mono-Italic.ttf,AutoFakeBold=1.5]",
+		},
+		{
+			[]string{path("Mono.ttf"), path("Mono-Bold.ttf"), path("Mono-Italic.ttf"),
+				path("Mono-BoldItalic.ttf")},
+			"{Mono.ttf}[Path=" + dir + ",BoldFont=Mono-Bold.ttf,ItalicFont=Mono-Italic.ttf," +
+				"BoldItalicFont=Mono-BoldItalic.ttf]",
+		},
+	}
+
+	for _, test := range tests {
+		if got := texFontFiles(test.paths); got != test.args {
+			t.Errorf("texFontFiles(%q): got %q, want %q", test.paths, got, test.args)
+		}
+	}
+}
@@ -5,44 +5,81 @@
 // goprint is a command used to print the source code of a Go package.
 //
 // The generated document is in HTML format, written on stdout and with CSS
-// specialized for printing.
+// specialized for printing.  Optionally, a PDF document can be generated
+// directly.
 package main
 
 import (
 	"flag"
 	"fmt"
-	"html/template"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/perillo/goprint/internal/cover"
 	"github.com/perillo/goprint/internal/css"
 	"github.com/perillo/goprint/internal/packages"
+	"github.com/perillo/goprint/internal/semantic"
+	"github.com/perillo/goprint/internal/theme"
 )
 
 // Command line flags.
 var (
-	test       = flag.Bool("test", false, "print _test.go source files")
-	module     = flag.Bool("m", false, "print all the packages in the module")
-	pageSize   = css.A4
-	pageMargin = css.PageMargin{
-		Top:    css.Dimension{2.5, css.Centimeter},
-		Right:  css.Dimension{1, css.Centimeter},
-		Bottom: css.Dimension{2.5, css.Centimeter},
-		Left:   css.Dimension{1, css.Centimeter},
+	test         = flag.Bool("test", false, "print _test.go source files")
+	module       = flag.Bool("m", false, "print all the packages in the module")
+	toc          = flag.Bool("toc", false, "print a table of contents (always enabled with -m)")
+	format       = flag.String("format", "html", "output format: html, pdf, markdown or latex")
+	lineNumbers  = flag.Bool("line-numbers", false, "print line numbers in Markdown code blocks")
+	texFragment  = flag.Bool("latex-fragment", false, "write a LaTeX fragment to include in another document, instead of a standalone document")
+	diffRevs     = flag.String("diff", "", "print the differences between two revisions `old..new`")
+	columns      = flag.Int("columns", 1, "number of columns per page")
+	nup          = flag.Int("nup", 1, "number of pages per sheet: 1 or 2")
+	wrap         = flag.Int("columns-width", 0, "wrap code lines longer than the specified number of characters (default computed from the page layout)")
+	fontFile     = flag.String("font-file", "", "comma separated TrueType or OpenType font `files` to embed in the document: regular, bold, italic and bold italic")
+	depsScope    = flag.String("deps", "", "print the packages and their transitive dependencies in `scope`: module, nostd or all")
+	declMode     = flag.Bool("decl", false, "print the declarations named by the arguments, with the form pkg.Name or pkg.Type.Method")
+	apiMode      = flag.Bool("api", false, "print only the package documentation and exported declarations")
+	blameMode    = flag.Bool("blame", false, "annotate each line with the last commit that changed it")
+	include      = flag.String("include", "", "include the non-Go files of the comma separated `kinds`: asm, c, embed, gomod, readme or all")
+	coverFile    = flag.String("coverprofile", "", "shade the lines using the coverage profile in `file`")
+	templateFile = flag.String("template", "", "HTML template `file` replacing the default document template")
+	cssFile      = flag.String("css", "", "CSS template `file` replacing the default stylesheet")
+	coverPage    = flag.Bool("cover-page", false, "print a cover page with the module and build metadata")
+	title        = flag.String("title", "", "document `title`, printed on the cover page")
+	author       = flag.String("author", "", "document `author`, printed on the cover page")
+	pageSize     = css.A4
+	pageMargin   = css.PageMargin{
+		Top:    css.Dimension{Value: 2.5, Unit: css.Centimeter},
+		Right:  css.Dimension{Value: 1, Unit: css.Centimeter},
+		Bottom: css.Dimension{Value: 2.5, Unit: css.Centimeter},
+		Left:   css.Dimension{Value: 1, Unit: css.Centimeter},
 	}
 	font = css.Font{
 		Family:     "Courier",
-		Size:       css.Dimension{10, css.Point},
-		LineHeight: css.Dimension{12, css.Point},
+		Size:       css.Dimension{Value: 10, Unit: css.Point},
+		LineHeight: css.Dimension{Value: 12, Unit: css.Point},
 	}
+	codeTheme theme.Theme
 )
 
 func init() {
 	flag.Var(&pageSize, "page-size", "page size")
 	flag.Var(&pageMargin, "page-margin", "page margin")
 	flag.Var(&font, "font", "font")
+
+	monochrome, _ := theme.Builtin("monochrome")
+	codeTheme = *monochrome
+	flag.Var(&codeTheme, "theme", "color and typography theme: "+
+		strings.Join(theme.Names(), ", ")+" or a theme `file`")
+
+	for i, name := range boxNames {
+		usage := "`template` of the " + strings.Replace(name, "-", " ", 1) +
+			" page margin box"
+		flag.Var(&boxFlags[i], name, usage)
+	}
 }
 
 func main() {
@@ -52,13 +89,116 @@ func main() {
 	// Parse command line.
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\tgoprint [flags] importpath\n")
+		fmt.Fprintf(os.Stderr, "\tgoprint [flags] [packages]\n")
+		fmt.Fprintf(os.Stderr, "\tgoprint -decl [flags] selector...\n")
+		fmt.Fprintf(os.Stderr, "\tgoprint [flags] file.go:first-last...\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 		os.Exit(2)
 	}
 	flag.Parse()
 
+	switch *format {
+	case "html", "pdf", "markdown", "latex":
+	default:
+		fmt.Fprintf(os.Stderr, "invalid output format: %q\n", *format)
+		flag.Usage()
+	}
+	if *texFragment && *format != "latex" {
+		fmt.Fprintf(os.Stderr, "-latex-fragment only supports the latex format\n")
+		flag.Usage()
+	}
+	if *columns < 1 {
+		fmt.Fprintf(os.Stderr, "invalid number of columns: %d\n", *columns)
+		flag.Usage()
+	}
+	if *wrap < 0 {
+		fmt.Fprintf(os.Stderr, "invalid columns width: %d\n", *wrap)
+		flag.Usage()
+	}
+	if *nup != 1 && *nup != 2 {
+		fmt.Fprintf(os.Stderr, "invalid number of pages per sheet: %d\n", *nup)
+		flag.Usage()
+	}
+	if *nup != 1 && *format == "latex" {
+		fmt.Fprintf(os.Stderr, "-nup is not supported with the latex format\n")
+		flag.Usage()
+	}
+
+	kinds, err := parseKinds(*include)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+	}
+	extraKinds = kinds
+
+	files, err := parseFontFiles(*fontFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+	}
+	if files != nil && *format == "markdown" {
+		fmt.Fprintf(os.Stderr, "-font-file is not supported with the markdown format\n")
+		flag.Usage()
+	}
+	if files != nil && files[0] == "" && *format != "html" {
+		fmt.Fprintf(os.Stderr, "-font-file requires the regular font with the %s format\n", *format)
+		flag.Usage()
+	}
+	if files != nil && *texFragment {
+		fmt.Fprintf(os.Stderr, "-font-file is not supported with -latex-fragment\n")
+		flag.Usage()
+	}
+	fontFiles = files
+
+	switch *depsScope {
+	case "", depsModule, depsNoStd, depsAll:
+	default:
+		fmt.Fprintf(os.Stderr, "invalid dependencies scope: %q\n", *depsScope)
+		flag.Usage()
+	}
+
+	if *diffRevs != "" && *format != "html" {
+		fmt.Fprintf(os.Stderr, "-diff only supports the html format\n")
+		flag.Usage()
+	}
+
+	// Print the declarations or the line ranges.
+	if *declMode || (flag.NArg() > 0 && isRange(flag.Arg(0))) {
+		if flag.NArg() == 0 || *module || *diffRevs != "" || *apiMode || *depsScope != "" {
+			flag.Usage()
+		}
+		printer := printRanges
+		if *declMode {
+			printer = printDecls
+		}
+		if err := printer(flag.Args(), *test); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	// Print the packages.
+	if *depsScope != "" && (*module || *diffRevs != "") {
+		fmt.Fprintf(os.Stderr, "-deps can not be used with -m and -diff\n")
+		flag.Usage()
+	}
+	if *depsScope != "" {
+		if err := printDeps(flag.Args(), *depsScope, *test); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+	if !*module && *diffRevs == "" {
+		if err := printPackages(flag.Args(), *test); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	var arg string
 	if flag.NArg() > 1 {
 		fmt.Fprintln(os.Stderr, "too many arguments")
@@ -68,25 +208,190 @@ func main() {
 		arg = flag.Arg(0)
 	}
 
-	// Print the package or module.
-	printer := printPackage
-	if *module {
-		printer = printModule
+	// Print the differences.
+	if *diffRevs != "" {
+		if err := printDiff(arg, *diffRevs, *test); err != nil {
+			log.Fatal(err)
+		}
+
+		return
 	}
-	if err := printer(arg, *test); err != nil {
+
+	// Print the module.
+	if err := printModule(arg, *test); err != nil {
 		log.Fatal(err)
 	}
 }
 
-// build returns the package pkg .go source files formatted in HTML.
+// writeFormat writes on w a document, in the format specified with -format
+// other than HTML, with the source files of all the packages in pkglist,
+// belonging to module mod, formatted by r.
+func writeFormat(w io.Writer, mod *packages.Module, pkglist []Package, r *renderer) error {
+	switch *format {
+	case "pdf":
+		return writePDF(w, mod, pkglist, r, fontFiles)
+	case "markdown":
+		return writeMarkdown(w, mod, pkglist)
+	case "latex":
+		return writeLaTeX(w, mod, pkglist, r, *texFragment)
+	}
+
+	panic("unknown output format: " + *format)
+}
+
+// sheetSize returns the size of the physical sheet.  With -nup 2, two logical
+// pages are printed side by side on a landscape sheet.
+func sheetSize() css.PageSize {
+	size := pageSize
+	if *nup == 2 {
+		size.Orientation = css.Landscape
+	}
+
+	return size
+}
+
+// wrapWidth returns the maximum number of characters in a code line, excluding
+// the line number, when each character is advance points wide.  Unless set
+// with -columns-width, it is computed from the page size, the page margins, the
+// number of columns and the font size.
+func wrapWidth(advance float64) int {
+	if *wrap > 0 {
+		return *wrap
+	}
+
+	width, _ := sheetSize().Size()
+	em := font.Size.Points()
+	w := width.Points()/float64(*nup) - pageMargin.Left.Points() - pageMargin.Right.Points()
+	w = (w - float64(*columns-1)*2*em) / float64(*columns) // 2em column gap
+	n := int(w/advance) - 4                                // line number
+	if *blameMode {
+		n -= blameWidth + 1
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	return n
+}
+
+// typecheck type checks the packages in pkglist.  The imported packages are
+// resolved using the export data of the packages named by patterns, with dir as
+// the working directory of the go command.
+//
+// If test is true, typecheck will include the packages _test.go files.
+//
+// Type checking is best effort: when the export data is not available, only
+// the identifiers declared in pkglist are classified.
+func typecheck(pkglist []*packages.Package, dir string, patterns []string, test bool) *semantic.Program {
+	exports, err := packages.Exports(dir, test, patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	srcs := make([]*semantic.Source, len(pkglist))
+	for i, pkg := range pkglist {
+		files := pkg.SourceFiles()
+		if test {
+			files = append(files, pkg.TestFiles()...)
+		}
+		srcs[i] = &semantic.Source{
+			ImportPath: pkg.ImportPath,
+			Name:       pkg.Name,
+			Files:      files,
+		}
+	}
+
+	return semantic.Load(srcs, exports)
+}
+
+// newRenderer returns a renderer for a document with the source files of all
+// the packages in pkglist.  The arguments are the same as for typecheck.
+//
+// When the -coverprofile flag is set, the coverage profile is loaded.  When the
+// -blame flag is set, git blame is run on all the source files.
+func newRenderer(pkglist []*packages.Package, dir string, patterns []string, test bool) (*renderer, error) {
+	srcs := make([][]string, len(pkglist))
+	for i, pkg := range pkglist {
+		srcs[i] = srcfiles(pkg, test)
+	}
+
+	return newFileRenderer(pkglist, srcs, dir, patterns, test)
+}
+
+// newFileRenderer is like newRenderer, but the document has only the source
+// files in srcs[i] of each package pkglist[i], that can include both the
+// _test.go and the other files.  test is only used to type check the packages.
+func newFileRenderer(pkglist []*packages.Package, srcs [][]string, dir string, patterns []string, test bool) (*renderer, error) {
+	var profiles map[string]*cover.Profile
+	if *coverFile != "" {
+		f, err := os.Open(*coverFile)
+		if err != nil {
+			return nil, fmt.Errorf("load coverage profile: %v", err)
+		}
+		defer f.Close()
+
+		profiles, err = cover.Parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("load coverage profile %s: %v", *coverFile, err)
+		}
+	}
+
+	files := make(map[string]string)
+	coverage := make(map[string]*cover.Profile)
+	blames := make(map[string][]blameLine)
+	for i, pkg := range pkglist {
+		for _, path := range srcs[i] {
+			name := pkg.ImportPath + "/" + filepath.Base(path)
+			files[path] = htmlID(name)
+			if p, ok := profiles[name]; ok {
+				coverage[path] = p
+			}
+			if *blameMode {
+				lines, err := blame(path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+
+					continue
+				}
+				blames[path] = lines
+			}
+		}
+	}
+
+	r := &renderer{
+		prog:  typecheck(pkglist, dir, patterns, test),
+		files: files,
+		cover: coverage,
+		blame: blames,
+		wrap:  wrapWidth(0.6 * font.Size.Points()), // assume Courier metrics
+	}
+
+	return r, nil
+}
+
+// srcfiles returns the package pkg .go source files to print.
 //
-// If test is true, build will use the package pkg _test.go files.
-func build(pkg *packages.Package, test bool) ([]File, error) {
-	srcfiles := pkg.SourceFiles()
+// If test is true, srcfiles will return the package pkg _test.go files.
+func srcfiles(pkg *packages.Package, test bool) []string {
 	if test {
-		srcfiles = pkg.TestFiles()
+		return pkg.TestFiles()
 	}
 
+	return pkg.SourceFiles()
+}
+
+// build returns the package pkg .go source files formatted in HTML by r,
+// followed by the non-Go files selected with -include.
+//
+// If test is true, build will use the package pkg _test.go files, and no
+// non-Go files.  In API mode, build returns a single file with the package
+// API.
+func build(pkg *packages.Package, test bool, r *renderer) ([]File, error) {
+	if *apiMode {
+		return buildAPI(pkg, r)
+	}
+	srcfiles := srcfiles(pkg, test)
+
 	files := make([]File, len(srcfiles))
 	for i, path := range srcfiles {
 		name := filepath.Base(path)
@@ -95,30 +400,50 @@ func build(pkg *packages.Package, test bool) ([]File, error) {
 			return nil, fmt.Errorf("read file %s: %v", path, err)
 		}
 		files[i] = File{
-			Name: name,
-			Code: render(name, input),
+			ID:       r.files[path],
+			Name:     name,
+			Path:     path,
+			Code:     r.render(path, input, nil),
+			Coverage: r.coverage(path),
+			input:    input,
+		}
+	}
+	if !test {
+		extra, err := buildExtra(pkg, r)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, extra...)
 	}
 
 	return files, nil
 }
 
-// buildModules returns all the module mod packages .go source files formatted in
-// HTML.
+// stdModule is the module reported for the packages in the standard library.
+var stdModule = &packages.Module{Path: "std"}
+
+// buildPackages returns all the packages in pkgs .go source files formatted in
+// HTML by r.
 //
 // If test is true, build will use each package _test.go files.
-func buildModule(mod *packages.Module, test bool) ([]Package, error) {
-	pkglist := make([]Package, len(mod.Packages))
-	for i, pkg := range mod.Packages {
-		files, err := build(pkg, test)
+func buildPackages(pkgs []*packages.Package, test bool, r *renderer) ([]Package, error) {
+	pkglist := make([]Package, len(pkgs))
+	for i, pkg := range pkgs {
+		files, err := build(pkg, test, r)
 		if err != nil {
 			return nil, err
 		}
 
 		p := Package{
+			ID:         htmlID(pkg.ImportPath),
 			ImportPath: pkg.ImportPath,
 			Name:       pkg.Name,
 			Files:      files,
+			Coverage:   sumCoverage(files),
+			Module:     pkg.Module,
+		}
+		if pkg.Standard && pkg.Module == nil {
+			p.Module = stdModule
 		}
 		pkglist[i] = p
 	}
@@ -126,52 +451,75 @@ func buildModule(mod *packages.Module, test bool) ([]Package, error) {
 	return pkglist, nil
 }
 
-// printPackage writes on stdout an HTML document with the all the .go source
-// files of the package named by path.
+// printPackages writes on stdout an HTML or PDF document with all the .go
+// source files of all the packages named by patterns.  When more than one
+// package is matched, the document has a section for each package, as in
+// module mode, but the packages can belong to different modules.
 //
-// It test is true, printPackage will use the package _test.go files.
-func printPackage(path string, test bool) error {
-	// Get package info.
-	pkg, err := packages.Load(path)
+// It test is true, printPackages will use the packages _test.go files.
+func printPackages(patterns []string, test bool) error {
+	// Get packages info.
+	pkgs, err := packages.LoadAll(patterns...)
 	if err != nil {
 		return err
 	}
+	if len(pkgs) == 1 {
+		return printPackage(pkgs[0], patterns, test)
+	}
 
+	return printPackageList(commonModule(pkgs), pkgs, "", patterns, test)
+}
+
+// commonModule returns the module containing all the packages in pkgs, or nil
+// if the packages belong to different modules.
+func commonModule(pkgs []*packages.Package) *packages.Module {
+	mod := pkgs[0].Module
+	for _, pkg := range pkgs[1:] {
+		if mod == nil || pkg.Module == nil || pkg.Module.Path != mod.Path {
+			return nil
+		}
+	}
+
+	return mod
+}
+
+// printPackage writes on stdout an HTML or PDF document with the all the .go
+// source files of the package pkg, named by patterns.
+//
+// It test is true, printPackage will use the package _test.go files.
+func printPackage(pkg *packages.Package, patterns []string, test bool) error {
 	// Format source files.
-	files, err := build(pkg, test)
+	r, err := newRenderer([]*packages.Package{pkg}, "", patterns, test)
 	if err != nil {
 		return err
 	}
-
-	// Load template.
-	tmpl := template.Must(template.New("index.html").Parse(index))
-	template.Must(tmpl.New("style.css").Parse(style))
-
-	// Render template.
-	ctx := struct {
-		Package    *packages.Package
-		Module     *packages.Module
-		Files      []File
-		PageSize   css.PageSize
-		PageMargin css.PageMargin
-		Font       css.Font
-	}{
-		pkg,
-		pkg.Module,
-		files,
-		pageSize,
-		pageMargin,
-		font,
+	files, err := build(pkg, test, r)
+	if err != nil {
+		return err
+	}
+	p := Package{
+		ID:         htmlID(pkg.ImportPath),
+		ImportPath: pkg.ImportPath,
+		Name:       pkg.Name,
+		Files:      files,
+		Coverage:   sumCoverage(files),
+		Module:     pkg.Module,
 	}
-	if err := tmpl.Execute(os.Stdout, ctx); err != nil {
-		return fmt.Errorf("execute: %v", err)
+	if *format != "html" {
+		return writeFormat(os.Stdout, pkg.Module, []Package{p}, r)
 	}
 
-	return nil
+	doc := newDocument(pkg.Module, []Package{p})
+	doc.Package = pkg
+	doc.Files = files
+	doc.Coverage = p.Coverage
+	doc.TOC = *toc
+
+	return writeHTML(os.Stdout, index, style, doc)
 }
 
-// printModule writes on stdout an HTML document with all the .go source files
-// of all the packages belonging to the module named by path.
+// printModule writes on stdout an HTML or PDF document with all the .go source
+// files of all the packages belonging to the module named by path.
 //
 // It test is true, printModule will use the packages _test.go files.
 func printModule(path string, test bool) error {
@@ -181,33 +529,40 @@ func printModule(path string, test bool) error {
 		return err
 	}
 
+	return printPackageList(mod, mod.Packages, mod.Dir, []string{"./..."}, test)
+}
+
+// printPackageList writes on stdout an HTML or PDF document with all the .go
+// source files of the packages in pkgs, with a section for each package, and
+// an index of the declarations.  The packages are named by patterns, with dir
+// as the working directory of the go command, and belong to module mod, if
+// not nil.
+//
+// It test is true, printPackageList will use the packages _test.go files.
+func printPackageList(mod *packages.Module, pkgs []*packages.Package, dir string, patterns []string, test bool) error {
 	// Format packages.
-	pkglist, err := buildModule(mod, test)
+	r, err := newRenderer(pkgs, dir, patterns, test)
 	if err != nil {
 		return err
 	}
-
-	// Load template.
-	tmpl := template.Must(template.New("index.html").Parse(indexmod))
-	template.Must(tmpl.New("style.css").Parse(stylemod))
-
-	// Render template.
-	ctx := struct {
-		Module     *packages.Module
-		Packages   []Package
-		PageSize   css.PageSize
-		PageMargin css.PageMargin
-		Font       css.Font
-	}{
-		mod,
-		pkglist,
-		pageSize,
-		pageMargin,
-		font,
+	pkglist, err := buildPackages(pkgs, test, r)
+	if err != nil {
+		return err
 	}
-	if err := tmpl.Execute(os.Stdout, ctx); err != nil {
-		return fmt.Errorf("execute: %v", err)
+	if *format != "html" {
+		return writeFormat(os.Stdout, mod, pkglist, r)
 	}
+	var index []Symbol
+	if !*apiMode {
+		// The declarations in API mode have no HTML id.
+		index, err = buildIndex(pkgs, test, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	doc := newDocument(mod, pkglist)
+	doc.Index = index
 
-	return nil
+	return writeHTML(os.Stdout, indexmod, stylemod, doc)
 }
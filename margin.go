@@ -0,0 +1,271 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// Page margin boxes, as in the CSS paged media module.
+const (
+	topLeft = iota
+	topCenter
+	topRight
+	bottomLeft
+	bottomCenter
+	bottomRight
+	numBoxes
+)
+
+// boxNames are the names of the page margin boxes, also used as flag names.
+var boxNames = [numBoxes]string{
+	"top-left", "top-center", "top-right",
+	"bottom-left", "bottom-center", "bottom-right",
+}
+
+// boxFlag is a flag with the template of a page margin box.
+type boxFlag struct {
+	text string
+	set  bool // an empty template removes the box
+}
+
+// String implements the Value interface.
+func (f *boxFlag) String() string {
+	return f.text
+}
+
+// Set implements the Value interface.
+func (f *boxFlag) Set(s string) error {
+	f.text = s
+	f.set = true
+
+	return nil
+}
+
+// boxFlags are the templates of the page margin boxes set on the command
+// line.
+var boxFlags [numBoxes]boxFlag
+
+// Default templates of the page margin boxes.  With two logical pages on each
+// sheet, the document has the boxes of the whole sheet.
+var (
+	defaultBoxes = [numBoxes]string{
+		topLeft:      "{{ .Package }}",
+		topRight:     "{{ .File }}",
+		bottomLeft:   "{{ .Module }}{{ with .Date }}\u2003{{ . }}{{ end }}",
+		bottomCenter: "{{ .Coverage }}",
+		bottomRight:  "page {{ page }}",
+	}
+	defaultSheetBoxes = [numBoxes]string{
+		topLeft:      "{{ .File }}",
+		topCenter:    "{{ .Package }}",
+		topRight:     "{{ .File }}",
+		bottomLeft:   "page {{ page }}",
+		bottomCenter: "{{ .Module }}{{ with .Date }}\u2003{{ . }}{{ end }}\u2003{{ .Coverage }}",
+		bottomRight:  "page {{ page }}",
+	}
+)
+
+// boxData is the data of the page margin box templates.  In the HTML
+// document, the fields that change from page to page are CSS functions.
+type boxData struct {
+	Package  string // import path of the package
+	File     string // name of the source file
+	Module   string // module of the package, with the version
+	Date     string // date of the module version, if all packages have one
+	Coverage string // statement coverage of the file and the package
+}
+
+// parseBoxes parses the templates of the page margin boxes set on the command
+// line, using the default templates for the page layout for the boxes not set.
+// Empty templates are nil.
+func parseBoxes() ([numBoxes]*texttemplate.Template, error) {
+	defaults := defaultBoxes
+	if *nup == 2 {
+		defaults = defaultSheetBoxes
+	}
+
+	var boxes [numBoxes]*texttemplate.Template
+	for i, f := range boxFlags {
+		text := defaults[i]
+		if f.set {
+			text = f.text
+		}
+		if text == "" {
+			continue
+		}
+
+		t := texttemplate.New(boxNames[i]).Funcs(boxFuncs("", "", ""))
+		if _, err := t.Parse(text); err != nil {
+			return boxes, fmt.Errorf("parse template: %v", err)
+		}
+		boxes[i] = t
+	}
+
+	return boxes, nil
+}
+
+// boxFuncs returns the functions available to the page margin box templates,
+// where page and pages are the values of the page number and of the number of
+// pages, and dir is the directory of the printed source files.
+func boxFuncs(page, pages, dir string) texttemplate.FuncMap {
+	m := texttemplate.FuncMap{
+		"page":     func() string { return page },
+		"pages":    func() string { return pages },
+		"revision": func() (string, error) { return revision(dir) },
+	}
+	for name, fn := range funcs {
+		m[name] = fn
+	}
+
+	return m
+}
+
+// revisions caches the results of revision, by directory.
+var revisions = make(map[string]result)
+
+// result is the result of a git command.
+type result struct {
+	s   string
+	err error
+}
+
+// revision returns the abbreviated commit hash of the git repository
+// containing dir.
+func revision(dir string) (string, error) {
+	r, ok := revisions[dir]
+	if !ok {
+		out, err := git(dir, "rev-parse", "--short", "HEAD")
+		r = result{strings.TrimSpace(string(out)), err}
+		revisions[dir] = r
+	}
+
+	return r.s, r.err
+}
+
+// sourceDir returns the directory of the source files of a document with the
+// packages in pkglist, belonging to module mod: the module directory, if
+// available, or the directory of the first source file.
+func sourceDir(mod *packages.Module, pkglist []Package) string {
+	if mod != nil && mod.Dir != "" {
+		return mod.Dir
+	}
+	for _, pkg := range pkglist {
+		for _, file := range pkg.Files {
+			if file.Path != "" {
+				return filepath.Dir(file.Path)
+			}
+		}
+	}
+
+	return "."
+}
+
+// marker delimits the markup, as CSS functions or LaTeX commands, in the
+// output of the page margin box templates.
+const marker = "\x00"
+
+// markup returns the markup s, delimited by marker.
+func markup(s string) string {
+	return marker + s + marker
+}
+
+// expandBox converts the output s of a page margin box template: text is
+// converted by quote, and the markup delimited by marker is copied.  The parts
+// are joined by sep.
+func expandBox(s string, quote func(string) string, sep string) string {
+	var parts []string
+	for i, part := range strings.Split(s, marker) {
+		switch {
+		case part == "":
+			continue
+		case i%2 == 1:
+			parts = append(parts, part)
+		default:
+			parts = append(parts, quote(part))
+		}
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// boxCSS returns the CSS page margin boxes for a document with package pkg,
+// in package mode, and module mod.  Both can be nil.  dir is the directory of
+// the source files.
+func boxCSS(pkg *packages.Package, mod *packages.Module, dir string) (template.CSS, error) {
+	boxes, err := parseBoxes()
+	if err != nil {
+		return "", err
+	}
+
+	data := boxData{
+		Package:  markup("string(package)"),
+		File:     markup("string(file)"),
+		Module:   markup("string(module)"),
+		Coverage: markup("string(coverage)"),
+	}
+	if pkg != nil {
+		data.Package = pkg.ImportPath
+		data.Module = ""
+	}
+	if mod != nil {
+		data.Module = mod.String()
+		data.Date = mod.Date()
+	}
+
+	var css []string
+	for i, t := range boxes {
+		if t == nil {
+			continue
+		}
+
+		d := data
+		page := "counter(page)"
+		if *nup == 2 {
+			// The left and right boxes refer to the left and right logical
+			// pages.
+			switch i {
+			case topLeft, bottomLeft:
+				d.File = markup("string(file, start)")
+				page = "counter(lpage)"
+			case topRight, bottomRight:
+				d.File = markup("string(file, last)")
+				page = "counter(rpage)"
+			}
+		}
+		var buf strings.Builder
+		t.Funcs(boxFuncs(markup(page), markup("counter(pages)"), dir))
+		if err := t.Execute(&buf, d); err != nil {
+			return "", fmt.Errorf("execute: %v", err)
+		}
+		content := expandBox(buf.String(), cssString, " ")
+		if content == "" {
+			continue
+		}
+
+		align, margin := "bottom", "margin-bottom"
+		if i >= bottomLeft {
+			align, margin = "top", "margin-top"
+		}
+		css = append(css, fmt.Sprintf("@%s {\n\t\t\tvertical-align: %s;\n"+
+			"\t\t\t%s: 1.5em;\n\t\t\tcontent: %s;\n\t\t}",
+			boxNames[i], align, margin, content))
+	}
+
+	return template.CSS(strings.Join(css, "\n\n\t\t")), nil
+}
+
+// cssString returns s as a CSS string.
+func cssString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\A `)
+
+	return `"` + r.Replace(s) + `"`
+}
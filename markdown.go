@@ -0,0 +1,176 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/perillo/goprint/internal/packages"
+	"github.com/perillo/goprint/internal/textfmt"
+)
+
+// writeMarkdown writes on w a Markdown document with the source files of all
+// the packages in pkglist, belonging to module mod.  Each package and each
+// file has a heading, with the same information printed in the HTML headers,
+// and the code of each file is in a fenced code block, with line numbers when
+// the -line-numbers flag is set.
+func writeMarkdown(w io.Writer, mod *packages.Module, pkglist []Package) error {
+	bw := bufio.NewWriter(w)
+
+	// metadata writes the module and the cover page after the document
+	// title.
+	metadata := func() {
+		if mod != nil {
+			fmt.Fprintf(bw, "_%s — %s_\n\n", mdEscape(mod.String()),
+				mdEscape(mod.Date()))
+		}
+		if *coverPage {
+			writeMarkdownCover(bw, newCover(mod, pkglist))
+		}
+	}
+
+	// With more packages, the document has a title and each package is a
+	// section; otherwise the package is the title.
+	level := 1
+	if len(pkglist) > 1 {
+		fmt.Fprintf(bw, "# %s\n\n", mdEscape(docTitle(mod)))
+		metadata()
+		level = 2
+	}
+
+	for _, pkg := range pkglist {
+		fmt.Fprintf(bw, "%s %s%s\n\n", strings.Repeat("#", level),
+			mdEscape(pkg.ImportPath), mdCoverage(pkg.Coverage))
+		if level == 1 {
+			metadata()
+		}
+		if mod == nil && pkg.Module != nil {
+			// The packages belong to different modules.
+			fmt.Fprintf(bw, "_%s_\n\n", mdEscape(pkg.Module.String()))
+		}
+		for _, file := range pkg.Files {
+			fmt.Fprintf(bw, "%s %s%s\n\n", strings.Repeat("#", level+1),
+				mdEscape(file.Name), mdCoverage(file.Coverage))
+			writeCodeBlock(bw, file)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write Markdown: %v", err)
+	}
+
+	return nil
+}
+
+// writeMarkdownCover writes the fields of the cover page c as a table.
+func writeMarkdownCover(w io.Writer, c *Cover) {
+	if c.Author != "" {
+		fmt.Fprintf(w, "%s\n\n", mdEscape(c.Author))
+	}
+	fmt.Fprintf(w, "| | |\n|---|---|\n")
+	for _, f := range c.Fields() {
+		value := strings.Replace(mdEscape(f[1]), "|", `\|`, -1)
+		fmt.Fprintf(w, "| **%s** | %s |\n", f[0], value)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// writeCodeBlock writes the source code of file as a fenced code block.  Only
+// the lines in the file ranges are included, and each run of skipped lines is
+// replaced by an elision marker.
+func writeCodeBlock(w io.Writer, file File) {
+	// The fence must be longer than any run of backticks in the code.
+	fence := "```"
+	for bytes.Contains(file.input, []byte(fence)) {
+		fence += "`"
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(file.input), "\n"), "\n")
+	width := len(strconv.Itoa(len(lines)))
+	if width < 3 {
+		width = 3
+	}
+	fmt.Fprintf(w, "%s%s\n", fence, mdLang(file.Path))
+	last := 0 // last line included
+	for i, line := range lines {
+		n := i + 1
+		if !inRanges(file.ranges, n) {
+			continue
+		}
+		if n > last+1 {
+			writeCodeLine(w, width, elision, "")
+		}
+		last = n
+		writeCodeLine(w, width, strconv.Itoa(n), strings.TrimSuffix(line, "\r"))
+	}
+	if last < len(lines) {
+		writeCodeLine(w, width, elision, "")
+	}
+	fmt.Fprintf(w, "%s\n\n", fence)
+}
+
+// writeCodeLine writes a code line, prefixed with the line number n when the
+// -line-numbers flag is set.  A line without a number is an elision marker.
+func writeCodeLine(w io.Writer, width int, n, line string) {
+	switch {
+	case *lineNumbers && line == "":
+		fmt.Fprintf(w, "%*s\n", width, n)
+	case *lineNumbers:
+		fmt.Fprintf(w, "%*s  %s\n", width, n, line)
+	case n == elision:
+		fmt.Fprintf(w, "%s\n", elision)
+	default:
+		fmt.Fprintf(w, "%s\n", line)
+	}
+}
+
+// mdLang returns the language of the fenced code block for the file named by
+// path.
+func mdLang(path string) string {
+	if filepath.Ext(path) == ".go" {
+		return "go"
+	}
+	switch textfmt.Detect(path) {
+	case textfmt.Asm:
+		return "asm"
+	case textfmt.C:
+		return "c"
+	case textfmt.GoMod:
+		return "go-mod"
+	}
+	if strings.HasSuffix(path, ".md") {
+		return "markdown"
+	}
+
+	return "text"
+}
+
+// mdCoverage returns the coverage c, as reported in the headings.
+func mdCoverage(c *Coverage) string {
+	if c == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%v covered)", c)
+}
+
+// mdEscape escapes the characters of s with a special meaning in Markdown
+// text.
+func mdEscape(s string) string {
+	var buf strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\`*_[]<>#", r) {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+
+	return buf.String()
+}
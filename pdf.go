@@ -0,0 +1,568 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/perillo/goprint/internal/cover"
+	"github.com/perillo/goprint/internal/goefmt"
+	"github.com/perillo/goprint/internal/packages"
+	"github.com/perillo/goprint/internal/pdf"
+	"github.com/perillo/goprint/internal/theme"
+)
+
+// Approximate ascent and descent of the fonts, relative to the font size.
+const (
+	ascent  = 0.8
+	descent = 0.2
+)
+
+// Colors used in the PDF document, matching the CSS style.
+var (
+	gray       = pdf.Gray(0.6)                       // #999
+	lightGreen = pdf.Color{R: 0.867, G: 1, B: 0.867} // #dfd
+	lightRed   = pdf.Color{R: 1, G: 0.867, B: 0.867} // #fdd
+	lightGray  = pdf.Gray(0.933)                     // #eee
+)
+
+// pdfLayout lays out Go source files in a PDF document, with the same layout
+// used for printing the HTML document.
+type pdfLayout struct {
+	doc *pdf.Document
+	r   *renderer
+	mod *packages.Module
+
+	fonts     [4]*pdf.Font // regular, bold, italic and bold italic
+	synthetic [4]int       // styles to synthesize for each font, as fonts index
+
+	size    float64 // font size
+	leading float64 // line height
+	width   float64 // logical page width
+	height  float64 // logical page height
+	top     float64
+	right   float64
+	bottom  float64
+	left    float64
+	nup     int     // logical pages per sheet
+	columns int     // columns per logical page
+	gap     float64 // gap between columns
+	colw    float64 // column width
+	wrap    int     // maximum number of characters in a code line
+	dir     string  // directory of the source files
+
+	coverage string           // coverage reported in the footer
+	curmod   *packages.Module // module reported in the footer
+
+	boxes [numBoxes]*texttemplate.Template // page margin boxes
+	pages []logicalPage                    // logical pages laid out
+
+	page *pdf.Page // current sheet
+	slot int       // logical page in the current sheet
+	col  int       // current column
+	ox   float64   // left of the current logical page
+	x    float64   // left of the current column
+	y    float64   // top of the next line box
+}
+
+// writePDF writes on w a PDF document with the source files of all the
+// packages in pkglist, belonging to module mod.  The source files are
+// formatted using r.
+//
+// When fontfiles is not empty, it has the TrueType fonts to embed in the
+// document, as with -font-file; otherwise the standard Courier fonts are used.
+//
+// With -nup 2, two logical pages are laid out side by side on each sheet,
+// and with -columns each logical page is split in columns.
+func writePDF(w io.Writer, mod *packages.Module, pkglist []Package, r *renderer, fontfiles []string) error {
+	width, height := sheetSize().Size()
+	l := &pdfLayout{
+		doc:     pdf.New(width.Points(), height.Points()),
+		r:       r,
+		mod:     mod,
+		size:    font.Size.Points(),
+		leading: font.LineHeight.Points(),
+		width:   width.Points() / float64(*nup),
+		height:  height.Points(),
+		top:     pageMargin.Top.Points(),
+		right:   pageMargin.Right.Points(),
+		bottom:  pageMargin.Bottom.Points(),
+		left:    pageMargin.Left.Points(),
+		nup:     *nup,
+		columns: *columns,
+	}
+	if l.leading == 0 {
+		l.leading = l.size
+	}
+	l.gap = 2 * l.size
+	area := l.width - l.left - l.right
+	l.colw = (area - float64(l.columns-1)*l.gap) / float64(l.columns)
+	if err := l.loadFonts(fontfiles); err != nil {
+		return err
+	}
+	l.wrap = wrapWidth(l.fonts[0].Width(" ") * l.size / 1000)
+
+	switch {
+	case *title != "":
+		l.doc.Title = *title
+	case len(pkglist) == 1:
+		l.doc.Title = pkglist[0].ImportPath
+	default:
+		l.doc.Title = docTitle(mod)
+	}
+	l.doc.Author = *author
+	l.doc.Creator = "goprint"
+	if mod != nil && mod.Time != nil {
+		l.doc.Date = *mod.Time
+	}
+
+	boxes, err := parseBoxes()
+	if err != nil {
+		return err
+	}
+	l.boxes = boxes
+	l.dir = sourceDir(mod, pkglist)
+
+	if *coverPage {
+		l.drawCover(newCover(mod, pkglist))
+	}
+	for _, pkg := range pkglist {
+		for _, file := range pkg.Files {
+			l.layout(pkg, file)
+		}
+	}
+	if err := l.drawBoxes(); err != nil {
+		return err
+	}
+	if _, err := l.doc.WriteTo(w); err != nil {
+		return fmt.Errorf("write PDF: %v", err)
+	}
+
+	return nil
+}
+
+// loadFonts loads the fonts to use in the document, from the font files in
+// paths with the variants in fontVariants order.  The regular font is
+// required; each missing variant uses the font with most of its styles, and
+// the other styles are synthesized.
+func (l *pdfLayout) loadFonts(paths []string) error {
+	if len(paths) == 0 {
+		l.fonts = [4]*pdf.Font{
+			pdf.Courier, pdf.CourierBold, pdf.CourierOblique,
+			pdf.CourierBoldOblique,
+		}
+
+		return nil
+	}
+
+	var loaded [4]*pdf.Font
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("load font: %v", err)
+		}
+		f, err := pdf.ParseTrueType(data)
+		if err != nil {
+			return fmt.Errorf("load font %s: %v", path, err)
+		}
+		loaded[i] = f
+	}
+	if loaded[0] == nil {
+		return fmt.Errorf("load font: missing regular font file")
+	}
+	for i := range l.fonts {
+		// Bold italic falls back to bold, then to italic.
+		for _, base := range [...]int{i, i &^ 2, i &^ 1, 0} {
+			if loaded[base] != nil {
+				l.fonts[i] = loaded[base]
+				l.synthetic[i] = i &^ base
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// style returns the text style to use for the specified font variant and
+// color.
+func (l *pdfLayout) style(bold, italic bool, c pdf.Color) pdf.TextStyle {
+	i := 0
+	if bold {
+		i |= 1
+	}
+	if italic {
+		i |= 2
+	}
+	style := pdf.TextStyle{
+		Font:  l.fonts[i],
+		Size:  l.size,
+		Color: c,
+	}
+	style.Bold = l.synthetic[i]&1 != 0
+	style.Italic = l.synthetic[i]&2 != 0
+
+	return style
+}
+
+// spanStyle returns the text style for a code span with the specified HTML
+// class, according to the theme.  It also reports whether the span must be
+// underlined and its background color, if any.
+func (l *pdfLayout) spanStyle(class []string) (style pdf.TextStyle, underline bool, background *pdf.Color) {
+	ts := codeTheme.Style(class)
+	color := pdf.Black
+	if ts.Color != nil {
+		color = pdfColor(*ts.Color)
+	}
+	if ts.Background != nil {
+		c := pdfColor(*ts.Background)
+		background = &c
+	}
+
+	return l.style(ts.Bold, ts.Italic, color), ts.Underline, background
+}
+
+// lineStyle returns the text style for line numbers and other annotations.
+func (l *pdfLayout) lineStyle() pdf.TextStyle {
+	style, _, _ := l.spanStyle([]string{"line"})
+
+	return style
+}
+
+// pdfColor converts a theme color to a PDF color.
+func pdfColor(c theme.Color) pdf.Color {
+	return pdf.Color{
+		R: float64(c.R) / 255,
+		G: float64(c.G) / 255,
+		B: float64(c.B) / 255,
+	}
+}
+
+// newPage starts a new logical page, with the headers and footers for the
+// source file name in the package with the specified import path.  A new sheet
+// is added when the current one is full.
+func (l *pdfLayout) newPage(importPath, name string) {
+	if l.page == nil || l.slot+1 >= l.nup {
+		l.page = l.doc.AddPage()
+		l.slot = 0
+	} else {
+		l.slot++
+	}
+	l.ox = float64(l.slot) * l.width
+	l.col = 0
+	l.x = l.ox + l.left
+	l.y = l.top
+
+	// The page margin boxes are drawn when the number of pages is known.
+	date := ""
+	if l.mod != nil {
+		date = l.mod.Date()
+	}
+	l.pages = append(l.pages, logicalPage{
+		page: l.page,
+		data: boxData{
+			Package:  importPath,
+			File:     name,
+			Module:   l.curmod.String(),
+			Date:     date,
+			Coverage: l.coverage,
+		},
+	})
+
+	left := l.ox + l.left
+	// Column rules.
+	for i := 1; i < l.columns; i++ {
+		x := left + float64(i)*(l.colw+l.gap) - l.gap/2
+		l.page.Line(x, l.top, x, l.height-l.bottom, 0.5, gray)
+	}
+}
+
+// logicalPage represents a logical page, with the data of its page margin
+// boxes.
+type logicalPage struct {
+	page *pdf.Page // sheet containing the logical page
+	data boxData
+}
+
+// drawBoxes draws the page margin boxes of all the sheets.  The header is
+// 1.5em above the page area, and the footer is 1.5em below.
+//
+// With two logical pages on each sheet, as in the HTML document, the left and
+// right boxes refer to the left and right logical pages, and the center boxes
+// to the left logical page.
+func (l *pdfLayout) drawBoxes() error {
+	regular := l.style(false, false, pdf.Black)
+	top := l.top - 1.5*l.size - descent*l.size
+	bottom := l.height - l.bottom + 1.5*l.size + ascent*l.size
+	sheetw := float64(l.nup) * l.width
+	pages := strconv.Itoa(len(l.pages))
+	for first := 0; first < len(l.pages); first++ {
+		last := first
+		if next := first + 1; next < len(l.pages) && l.pages[next].page == l.pages[first].page {
+			last = next
+		}
+		sheet := l.pages[first].page
+		for i, t := range l.boxes {
+			if t == nil {
+				continue
+			}
+
+			n := first
+			if i == topRight || i == bottomRight {
+				n = last
+			}
+			var buf strings.Builder
+			t.Funcs(boxFuncs(strconv.Itoa(n+1), pages, l.dir))
+			if err := t.Execute(&buf, l.pages[n].data); err != nil {
+				return fmt.Errorf("execute: %v", err)
+			}
+			s := buf.String()
+			y := top
+			if i >= bottomLeft {
+				y = bottom
+			}
+			var x float64
+			switch i {
+			case topLeft, bottomLeft:
+				x = l.left
+			case topCenter, bottomCenter:
+				x = (sheetw - l.textWidth(s, regular)) / 2
+			case topRight, bottomRight:
+				x = sheetw - l.right - l.textWidth(s, regular)
+			}
+			l.text(sheet, x, y, s, regular)
+		}
+		first = last
+	}
+
+	return nil
+}
+
+// text draws the text s on page, as pdf.Page.Text.  Em spaces are drawn as
+// blank space one em wide, since the standard fonts do not have them.
+func (l *pdfLayout) text(page *pdf.Page, x, y float64, s string, style pdf.TextStyle) {
+	for i, part := range strings.Split(s, "\u2003") {
+		if i > 0 {
+			x += style.Size
+		}
+		x += page.Text(x, y, part, style)
+	}
+}
+
+// textWidth returns the width of the text s, as drawn by text.
+func (l *pdfLayout) textWidth(s string, style pdf.TextStyle) float64 {
+	parts := strings.Split(s, "\u2003")
+	w := float64(len(parts)-1) * style.Size
+	for _, part := range parts {
+		w += style.Font.Width(part) * style.Size / 1000
+	}
+
+	return w
+}
+
+// drawCover draws the cover page c on its own sheet, without page margin
+// boxes, matching the CSS style.
+func (l *pdfLayout) drawCover(c *Cover) {
+	page := l.doc.AddPage()
+	width := l.width * float64(l.nup)
+	regular := l.style(false, false, pdf.Black)
+	bold := l.style(true, false, pdf.Black)
+	center := func(y float64, s string, style pdf.TextStyle) {
+		l.text(page, (width-l.textWidth(s, style))/2, y, s, style)
+	}
+
+	// As the CSS padding, the space above is relative to the width.
+	y := l.top + 0.3*(width-l.left-l.right)
+	if c.Title != "" {
+		style := l.style(true, false, pdf.Black)
+		style.Size *= 2
+		y += 2 * l.leading
+		center(y, c.Title, style)
+		y += l.leading
+	}
+	if c.Author != "" {
+		y += 2 * l.leading
+		center(y, c.Author, regular)
+	}
+	y += 2 * l.leading
+
+	// The fields are in a table with two columns, centered.
+	fields := c.Fields()
+	var lw, vw float64
+	for _, f := range fields {
+		if w := l.textWidth(f[0], bold); w > lw {
+			lw = w
+		}
+		if w := l.textWidth(f[1], regular); w > vw {
+			vw = w
+		}
+	}
+	x := (width - lw - l.size - vw) / 2
+	if x < l.left {
+		x = l.left
+	}
+	for _, f := range fields {
+		y += 1.25 * l.leading
+		l.text(page, x, y, f[0], bold)
+		l.text(page, x+lw+l.size, y, f[1], regular)
+	}
+}
+
+// nextColumn moves to the next column, starting a new logical page when the
+// current one is full.
+func (l *pdfLayout) nextColumn(importPath, name string) {
+	if l.col+1 >= l.columns {
+		l.newPage(importPath, name)
+
+		return
+	}
+	l.col++
+	l.x = l.ox + l.left + float64(l.col)*(l.colw+l.gap)
+	l.y = l.top
+}
+
+// layout lays out the source file of package pkg, starting from a new page.
+func (l *pdfLayout) layout(pkg Package, file File) {
+	l.coverage = coverageLabel(file.Coverage, pkg.Coverage)
+	l.curmod = l.mod
+	if l.mod == nil {
+		// The packages belong to different modules.
+		l.curmod = pkg.Module
+	}
+	l.newPage(pkg.ImportPath, file.Name)
+	l.page.Dest(file.ID, l.y)
+
+	n := 1
+	last := 0 // last line included
+	status := l.r.lineStatus(file.Path, file.input)
+	for line := range formatFile(file.Path, file.input) {
+		if !inRanges(file.ranges, n) {
+			n++
+
+			continue
+		}
+		if n > last+1 {
+			l.elision(pkg, file)
+		}
+		last = n
+		shade := cover.NotInstrumented
+		if n <= len(status) {
+			shade = status[n-1]
+		}
+		rows := goefmt.Wrap(line, l.wrap, tabsize)
+		for i, row := range rows {
+			if l.y+l.leading > l.height-l.bottom {
+				l.nextColumn(pkg.ImportPath, file.Name)
+			}
+			switch shade {
+			case cover.Covered:
+				l.page.Rect(l.x, l.y, l.colw, l.leading, lightGreen)
+			case cover.Uncovered:
+				l.page.Rect(l.x, l.y, l.colw, l.leading, lightRed)
+			}
+			l.line(file.Path, n, i > 0, i > 0 && split(rows[i-1], row), row)
+		}
+		n++
+	}
+	if last < lineCount(file.input) {
+		l.elision(pkg, file)
+	}
+}
+
+// elision draws the marker for a run of skipped lines.
+func (l *pdfLayout) elision(pkg Package, file File) {
+	if l.y+l.leading > l.height-l.bottom {
+		l.nextColumn(pkg.ImportPath, file.Name)
+	}
+	base := l.y + (l.leading-l.size)/2 + ascent*l.size
+	l.y += l.leading
+
+	l.page.Text(l.x, base, fmt.Sprintf("%3s", elision), l.lineStyle())
+}
+
+// line draws the code line number n of the source file named by path.  When
+// cont is true, line is the continuation of a wrapped line; when rest is also
+// true, its first span is the rest of a token split by goefmt.Wrap.
+func (l *pdfLayout) line(path string, n int, cont, rest bool, line goefmt.Line) {
+	top := l.y
+	base := top + (l.leading-l.size)/2 + ascent*l.size
+	l.y += l.leading
+
+	regular := l.style(false, false, pdf.Black)
+	tab := tabsize * regular.Font.Width(" ") * l.size / 1000
+
+	// draw draws the text s with style, expanding tabs.  When underline is
+	// true, the text is underlined.
+	x := l.x
+	draw := func(s string, style pdf.TextStyle, underline bool) {
+		x0 := x
+		for i, part := range strings.Split(s, "\t") {
+			if i > 0 {
+				// Move to the next tab stop.
+				x = l.x + (float64(int((x-l.x)/tab+1e-6))+1)*tab
+			}
+			if strings.TrimSpace(part) == "" {
+				x += style.Font.Width(part) * l.size / 1000
+
+				continue
+			}
+			x += l.page.Text(x, base, part, style)
+		}
+		if underline {
+			y := base + 0.1*l.size
+			l.page.Line(x0, y, x, y, l.size/20, style.Color)
+		}
+	}
+
+	if cont {
+		draw(fmt.Sprintf("%3s", continuation), l.lineStyle(), false)
+	} else {
+		draw(fmt.Sprintf("%3d", n), l.lineStyle(), false)
+	}
+	if s, group, ok := l.r.blameAnnotation(path, n, cont); ok {
+		draw(" ", regular, false)
+		if group%2 == 1 {
+			w := regular.Font.Width(s) * l.size / 1000
+			l.page.Rect(x, top, w, l.leading, lightGray)
+		}
+		draw(s, l.style(false, false, gray), false)
+	}
+	draw(" ", regular, false)
+	for i, s := range line {
+		if s.Code == "" {
+			draw(s.Whitespace, regular, false)
+
+			continue
+		}
+		style, underline, background := l.spanStyle(l.r.spanClass(path, s))
+		x0 := x
+		if background != nil {
+			w := style.Font.Width(s.Code) * l.size / 1000
+			l.page.Rect(x, top, w, l.leading, *background)
+		}
+		draw(s.Code, style, underline)
+		if id, decl, ok := l.r.xref(path, s); ok {
+			switch {
+			case isDecl(path, s, decl, rest && i == 0):
+				l.page.Dest(id, top)
+			case decl.Filename == path && decl.Offset == s.Offset:
+				// The rest of the declaration.
+			default:
+				l.page.Link(x0, top, x-x0, l.leading, id)
+			}
+		}
+		draw(s.Whitespace, regular, false)
+	}
+}
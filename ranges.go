@@ -0,0 +1,172 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/perillo/goprint/internal/packages"
+)
+
+// isRange returns true if the argument s has the form of a line range.
+func isRange(s string) bool {
+	i := strings.LastIndexByte(s, ':')
+
+	return i > 0 && strings.HasSuffix(s[:i], ".go")
+}
+
+// parseRange parses a line range argument, with the form file.go:first-last or
+// file.go:line.  It returns the path of the file and the range of lines.
+func parseRange(s string) (string, lineRange, error) {
+	if !isRange(s) {
+		return "", lineRange{}, fmt.Errorf("invalid line range %q", s)
+	}
+	i := strings.LastIndexByte(s, ':')
+	path, spec := s[:i], s[i+1:]
+
+	first, last := spec, spec
+	if j := strings.IndexByte(spec, '-'); j >= 0 {
+		first, last = spec[:j], spec[j+1:]
+	}
+	m, err1 := strconv.Atoi(first)
+	n, err2 := strconv.Atoi(last)
+	if err1 != nil || err2 != nil || m < 1 || n < m {
+		return "", lineRange{}, fmt.Errorf("invalid line range %q", s)
+	}
+
+	return path, lineRange{m, n}, nil
+}
+
+// excerpt describes the line ranges to print from a source file.
+type excerpt struct {
+	path   string // absolute path of the source file
+	ranges []lineRange
+}
+
+// printRanges writes on stdout an HTML or PDF document with the line ranges
+// named by args, with the form file.go:first-last.  The files are printed in
+// the order they first appear, with the ranges of each file sorted and the
+// original line numbers; an elision marker is printed in place of the skipped
+// lines.
+//
+// Cross references are not available, since the declaration of an
+// identifier may not be in the document.
+//
+// If test is true, or a file is a _test.go file, the packages _test.go files
+// are type checked.  The coverage profile and the blame annotations are
+// loaded for all the printed files.
+func printRanges(args []string, test bool) error {
+	mod, pkglist, r, err := buildRanges(args, test)
+	if err != nil {
+		return err
+	}
+
+	return printExcerpts(mod, pkglist, r)
+}
+
+// buildRanges returns the line ranges named by args formatted in HTML,
+// grouped by package, with the module of the first package and the renderer
+// used.  The arguments are the same as for printRanges.
+func buildRanges(args []string, test bool) (*packages.Module, []Package, *renderer, error) {
+	// Collect the ranges of each file.
+	var excerpts []*excerpt
+	byPath := make(map[string]*excerpt)
+	for _, arg := range args {
+		path, lr, err := parseRange(arg)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		e, ok := byPath[path]
+		if !ok {
+			e = &excerpt{path: path}
+			byPath[path] = e
+			excerpts = append(excerpts, e)
+		}
+		e.ranges = append(e.ranges, lr)
+		if strings.HasSuffix(path, "_test.go") {
+			test = true
+		}
+	}
+
+	// Load the packages containing the files.
+	var (
+		pkgs     []*packages.Package
+		srcs     [][]string // printed files of each package
+		patterns []string
+	)
+	owner := make(map[string]int) // index in pkgs, by directory
+	for _, e := range excerpts {
+		dir := filepath.Dir(e.path)
+		if i, ok := owner[dir]; ok {
+			srcs[i] = append(srcs[i], e.path)
+
+			continue
+		}
+		pkg, err := packages.Load(dir)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		owner[dir] = len(pkgs)
+		pkgs = append(pkgs, pkg)
+		srcs = append(srcs, []string{e.path})
+		patterns = append(patterns, dir)
+	}
+
+	// Format the files.
+	r, err := newFileRenderer(pkgs, srcs, "", patterns, test)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	r.files = nil // disable cross references
+
+	var pkglist []Package
+	for _, e := range excerpts {
+		input, err := ioutil.ReadFile(e.path)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("read file %s: %v", e.path, err)
+		}
+		sort.Slice(e.ranges, func(i, j int) bool {
+			return e.ranges[i].First < e.ranges[j].First
+		})
+		if lr, n := e.ranges[len(e.ranges)-1], lineCount(input); lr.First > n {
+			return nil, nil, nil, fmt.Errorf("line range %d-%d: file %s has %d lines",
+				lr.First, lr.Last, e.path, n)
+		}
+
+		pkg := pkgs[owner[filepath.Dir(e.path)]]
+		name := filepath.Base(e.path)
+		file := File{
+			ID:     htmlID(pkg.ImportPath + "/" + name),
+			Name:   name,
+			Path:   e.path,
+			Code:   r.render(e.path, input, e.ranges),
+			input:  input,
+			ranges: e.ranges,
+		}
+
+		// Group consecutive files of the same package.
+		if n := len(pkglist); n == 0 || pkglist[n-1].ImportPath != pkg.ImportPath {
+			pkglist = append(pkglist, Package{
+				ID:         htmlID(pkg.ImportPath),
+				ImportPath: pkg.ImportPath,
+				Name:       pkg.Name,
+				Module:     pkg.Module,
+			})
+		}
+		p := &pkglist[len(pkglist)-1]
+		p.Files = append(p.Files, file)
+	}
+
+	return pkgs[0].Module, pkglist, r, nil
+}
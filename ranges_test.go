@@ -0,0 +1,76 @@
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBuildRanges tests that the line ranges are grouped by file and package,
+// with the package metadata, including the module.
+func TestBuildRanges(t *testing.T) {
+	const modpath = "github.com/perillo/goprint"
+
+	args := []string{
+		"internal/diff/diff.go:10-12",
+		"internal/diff/diff_test.go:1-2",
+		"internal/cover/cover.go:1",
+		"internal/diff/diff.go:1-3",
+	}
+	mod, pkglist, _, err := buildRanges(args, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod == nil || mod.Path != modpath {
+		t.Errorf("got module %v, want %s", mod, modpath)
+	}
+
+	type file struct {
+		name   string
+		ranges []lineRange
+	}
+	var tests = []struct {
+		importPath string
+		name       string
+		files      []file
+	}{
+		{modpath + "/internal/diff", "diff", []file{
+			{"diff.go", []lineRange{{1, 3}, {10, 12}}},
+			{"diff_test.go", []lineRange{{1, 2}}},
+		}},
+		{modpath + "/internal/cover", "cover", []file{
+			{"cover.go", []lineRange{{1, 1}}},
+		}},
+	}
+	if len(pkglist) != len(tests) {
+		t.Fatalf("got %d packages, want %d", len(pkglist), len(tests))
+	}
+	for i, test := range tests {
+		pkg := pkglist[i]
+		if pkg.ImportPath != test.importPath || pkg.Name != test.name {
+			t.Errorf("package %d: got %s (%s), want %s (%s)", i, pkg.ImportPath,
+				pkg.Name, test.importPath, test.name)
+		}
+		if pkg.ID != htmlID(test.importPath) {
+			t.Errorf("package %d: got ID %q, want %q", i, pkg.ID, htmlID(test.importPath))
+		}
+		if pkg.Module == nil || pkg.Module.Path != modpath {
+			t.Errorf("package %d: got module %v, want %s", i, pkg.Module, modpath)
+		}
+		if len(pkg.Files) != len(test.files) {
+			t.Errorf("package %d: got %d files, want %d", i, len(pkg.Files), len(test.files))
+
+			continue
+		}
+		for j, file := range pkg.Files {
+			want := test.files[j]
+			if file.Name != want.name || !reflect.DeepEqual(file.ranges, want.ranges) {
+				t.Errorf("package %d, file %d: got %s %v, want %s %v", i, j,
+					file.Name, file.ranges, want.name, want.ranges)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// vim: set filetype=css :
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Definition of the additional CSS template for diffs.
+
+package main
+
+var stylediff = `
+.diff .hunk, .diff .ctx, .diff .add, .diff .del {
+	display: block;
+}
+
+.diff .hunk {
+	margin-top: 0.5em;
+	color: #999;
+}
+
+.diff .hunk:first-child {
+	margin-top: 0;
+}
+
+.diff .add {
+	background-color: #dfd;
+}
+
+.diff .del {
+	background-color: #fdd;
+}
+
+.diff .add .mark, .diff .del .mark {
+	font-weight: bold;
+}
+`
@@ -8,6 +8,7 @@
 package main
 
 var stylemod = `
+{{ .FontFaces }}
 * {
 	margin: 0;
 	padding: 0;
@@ -24,34 +25,76 @@ code {
 	display: block;
 }
 
-.line {
-	color: #999;
-}
-
 .operator, .ident {
 	font-style: normal;
 	font-weight: normal;
 }
 
-.keyword {
-	font-weight: bold;
+{{ .Theme }}
+.blame {
+	color: #999;
+}
+
+.blame.odd {
+	background-color: #eee;
+}
+
+.covered {
+	background-color: #dfd;
+}
+
+.uncovered {
+	background-color: #fdd;
+}
+
+a.xref {
+	color: inherit;
+	text-decoration: none;
+}
+
+.toc ul {
+	list-style: none;
+}
+
+.toc ul ul {
+	margin-left: 2em;
+}
+
+.toc a {
+	color: inherit;
+	text-decoration: none;
+}
+
+.index a {
+	color: inherit;
+	text-decoration: none;
+}
+
+.index td {
+	padding-right: 2em;
 }
 
-.builtin {
-	font-weight: bold;
-	font-style: italic;
+.index td.kind, .index td.path, .index td.filename {
+	color: #999;
 }
 
-.literal {
-	font-style: italic;
+.cover {
+	text-align: center;
 }
 
-.comment {
-	font-style: oblique;
+.cover > h1 {
+	font-size: 2em;
+	margin-bottom: 1em;
 }
 
-.invalid {
-	background-color: red;
+.cover table {
+	margin: 2em auto;
+	border-spacing: 1em 0.25em;
+	text-align: left;
+}
+
+.cover th {
+	vertical-align: top;
 }
 
 @media print {
@@ -59,36 +102,88 @@ code {
 		size: {{ .PageSize }};
 		margin: {{ .PageMargin }};
 		font-size: {{ .Font.Size }};
+		{{ if eq .NUp 2 }}
+		counter-increment: page 1 lpage 2 rpage 2;
+		{{ else }}
 		counter-increment: page 1;
+		{{ end }}
+
+		{{ .Margins }}
+	}
+
+	{{ if eq .NUp 2 }}
+	/* Two logical pages on each sheet: each half of the sheet is a column. */
+	@page :first {
+		counter-reset: lpage -1 rpage 0;
+	}
+
+	body {
+		column-count: 2;
+		column-gap: calc({{ .PageMargin.Left }} + {{ .PageMargin.Right }});
+	}
+	{{ end }}
+
+	{{ if gt .Columns 1 }}
+	.file > pre {
+		column-count: {{ .Columns }};
+		column-gap: 2em;
+		column-rule: thin solid #999;
+	}
+	{{ end }}
+
+	/* The cover page has no running headers and it is not numbered. */
+	@page cover {
+		counter-increment: none;
 
 		@top-left {
-			vertical-align: bottom;
-			margin-bottom: 1.5em;
-			content: string(package);
+			content: none;
+		}
+
+		@top-center {
+			content: none;
 		}
 
 		@top-right {
-			vertical-align: bottom;
-			margin-bottom: 1.5em;
-			content: string(file);
+			content: none;
 		}
 
 		@bottom-left {
-			vertical-align: top;
-			margin-top: 1.5em;
-			content: "{{ .Module }}" "\2003" "{{ .Module.Date }}";
+			content: none;
+		}
+
+		@bottom-center {
+			content: none;
 		}
 
 		@bottom-right {
-			vertical-align: top;
-			margin-top: 1.5em;
-			content: "page " counter(page);
+			content: none;
 		}
 	}
 
+	.cover {
+		page: cover;
+		page-break-after: always;
+		padding-top: 30%;
+	}
+
+	.cover > h1 {
+		display: block;
+	}
+
+	a.xref.external::after {
+		content: " \2192 p. " target-counter(attr(href), page);
+		font-size: 0.8em;
+		color: #999;
+	}
+
 	.package {
 		page-break-after: always;
+		{{ if .Module }}
 		string-set: package attr(data-package);
+		{{ else }}
+		/* The packages belong to different modules. */
+		string-set: package attr(data-package), module attr(data-module);
+		{{ end }}
 	}
 
 	.package > h1 {
@@ -100,8 +195,12 @@ code {
 	}
 
 	.file {
+		{{ if eq .NUp 2 }}
+		break-after: column;
+		{{ else }}
 		page-break-after: always;
-		string-set: file attr(data-file);
+		{{ end }}
+		string-set: file attr(data-file), coverage attr(data-coverage);
 	}
 
 	.file:last-of-type {
@@ -111,6 +210,35 @@ code {
 	h1, h2, h3 {
 		display: none;
 	}
+
+	.toc {
+		page-break-after: always;
+		string-set: package attr(data-package), file "";
+	}
+
+	.toc > h2 {
+		display: block;
+		margin-bottom: 1em;
+		font-size: 1.5em;
+	}
+
+	.toc a::after {
+		content: leader(".") target-counter(attr(href), page);
+	}
+
+	.index {
+		string-set: package attr(data-package), file "";
+	}
+
+	.index > h2 {
+		display: block;
+		margin-bottom: 1em;
+		font-size: 1.5em;
+	}
+
+	.index td.page a::after {
+		content: target-counter(attr(href), page);
+	}
 }
 
 @media screen {
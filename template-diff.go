@@ -0,0 +1,52 @@
+// vim: set filetype=html :
+// Copyright 2026 Manlio Perillo. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Definition of the HTML template for diffs.
+
+package main
+
+var indexdiff = `<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8" />
+		<style type="text/css">
+			{{ template "style.css" . }}
+			{{ template "diff.css" . }}
+		</style>
+
+		<title>{{ .Title }}</title>
+	</head>
+	<body>
+		{{ with .Cover }}
+		<section class="cover">
+			<h1>{{ .Title }}</h1>
+			{{ with .Author }}
+			<p class="author">{{ . }}</p>
+			{{ end }}
+			<table>
+				{{ range .Fields }}
+				<tr>
+					<th>{{ index . 0 }}</th>
+					<td>{{ index . 1 }}</td>
+				</tr>
+				{{ end }}
+			</table>
+		</section>
+		{{ end }}
+	  <h1>{{ .Title }}</h1>
+	  {{ range .Packages }}
+		<section class="package" id="{{ .ID }}" data-package="{{ .ImportPath }}">
+			<h2>{{ .ImportPath }}</h2>
+			{{ range .Files }}
+			<section class="file" id="{{ .ID }}" data-file="{{ .Name }}">
+				<h3>{{ .Name }}</h3>
+				<pre class="diff"><code>{{ .Code }}</code></pre>
+			</section>
+			{{ end }}
+		</section>
+		{{ end }}
+	</body>
+</html>
+`
@@ -15,21 +15,73 @@ var indexmod = `<!DOCTYPE html>
 			{{ template "style.css" . }}
 		</style>
 
-		<title>{{ .Module }}</title>
+		<title>{{ .Title }}</title>
 	</head>
 	<body>
-	  <h1>{{ .Module }}</h1>
-	  {{ range .Packages }}
-		<section class="package" data-package="{{ .ImportPath }}">
-			<h2>{{ .ImportPath }}</h2>
+		{{ with .Cover }}
+		<section class="cover">
+			<h1>{{ .Title }}</h1>
+			{{ with .Author }}
+			<p class="author">{{ . }}</p>
+			{{ end }}
+			<table>
+				{{ range .Fields }}
+				<tr>
+					<th>{{ index . 0 }}</th>
+					<td>{{ index . 1 }}</td>
+				</tr>
+				{{ end }}
+			</table>
+		</section>
+		{{ end }}
+	  <h1>{{ with .Module }}{{ . }}{{ else }}{{ .Title }}{{ end }}</h1>
+		<nav class="toc" data-package="Contents">
+			<h2>Contents</h2>
+			<ul>
+				{{ range .Packages }}
+				<li>
+					<a href="#{{ .ID }}">{{ .ImportPath }}</a>
+					<ul>
+						{{ range .Files }}
+						<li><a href="#{{ .ID }}">{{ .Name }}</a></li>
+						{{ end }}
+					</ul>
+				</li>
+				{{ end }}
+				{{ if .Index }}
+				<li><a href="#index">Index</a></li>
+				{{ end }}
+			</ul>
+		</nav>
+	  {{ range $pkg := .Packages }}
+		<section class="package" id="{{ .ID }}" data-package="{{ .ImportPath }}"
+			data-module="{{ .Module }}">
+			<h2>{{ .ImportPath }}{{ with .Coverage }} ({{ . }} covered){{ end }}</h2>
 			{{ range .Files }}
-			<section class="file" data-file="{{ .Name }}">
-				<h3>{{ .Name }}</h3>
+			<section class="file" id="{{ .ID }}" data-file="{{ .Name }}"
+				data-coverage="{{ coverage .Coverage $pkg.Coverage }}">
+				<h3>{{ .Name }}{{ with .Coverage }} ({{ . }} covered){{ end }}</h3>
 				<pre><code>{{ .Code }}</code></pre>
 			</section>
 			{{ end }}
 		</section>
 		{{ end }}
+		{{ if .Index }}
+		<section class="index" id="index" data-package="Index">
+			<h2>Index</h2>
+			<table>
+				{{ range .Index }}
+				<tr>
+					<td class="name">{{ if .ID }}<a href="#{{ .ID }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}</td>
+					<td class="kind">{{ .Kind }}</td>
+					<td class="path">{{ .ImportPath }}</td>
+					<td class="filename">{{ .File }}</td>
+					<td class="page">{{ with .ID }}<a href="#{{ . }}"></a>{{ end }}</td>
+				</tr>
+				{{ end }}
+			</table>
+		</section>
+		{{ end }}
 	</body>
 </html>
 `
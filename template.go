@@ -18,11 +18,38 @@ var index = `<!DOCTYPE html>
 		<title>{{ .Package }}</title>
 	</head>
 	<body>
+		{{ with .Cover }}
+		<section class="cover">
+			<h1>{{ .Title }}</h1>
+			{{ with .Author }}
+			<p class="author">{{ . }}</p>
+			{{ end }}
+			<table>
+				{{ range .Fields }}
+				<tr>
+					<th>{{ index . 0 }}</th>
+					<td>{{ index . 1 }}</td>
+				</tr>
+				{{ end }}
+			</table>
+		</section>
+		{{ end }}
+		{{ if .TOC }}
+		<nav class="toc">
+			<h2>Contents</h2>
+			<ul>
+				{{ range .Files }}
+				<li><a href="#{{ .ID }}">{{ .Name }}</a></li>
+				{{ end }}
+			</ul>
+		</nav>
+		{{ end }}
 		<section class="package">
-			<h1>{{ .Package }}</h1>
+			<h1>{{ .Package }}{{ with .Coverage }} ({{ . }} covered){{ end }}</h1>
 			{{ range .Files }}
-			<section class="file" data-file="{{ .Name }}">
-				<h2>{{ .Name }}</h2>
+			<section class="file" id="{{ .ID }}" data-file="{{ .Name }}"
+				data-coverage="{{ coverage .Coverage $.Coverage }}">
+				<h2>{{ .Name }}{{ with .Coverage }} ({{ . }} covered){{ end }}</h2>
 				<pre><code>{{ .Code }}</code></pre>
 			</section>
 			{{ end }}